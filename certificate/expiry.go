@@ -0,0 +1,163 @@
+// Package certificate provides helpers to check the validity of x509 certificates
+package certificate
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/NETWAYS/go-check"
+	"github.com/NETWAYS/go-check/result"
+)
+
+// Seconds per day, used to scale the thresholds in days to the perfdata in seconds
+const secondsPerDay = 24 * 60 * 60
+
+// LoadFile reads all PEM encoded certificates from a file
+func LoadFile(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read certificate file: %w", err)
+	}
+
+	return ParsePEM(data)
+}
+
+// ParsePEM parses all PEM encoded certificates, other PEM blocks (e.g. keys) are skipped
+func ParsePEM(data []byte) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+
+	for {
+		var block *pem.Block
+
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse certificate: %w", err)
+		}
+
+		chain = append(chain, cert)
+	}
+
+	if len(chain) == 0 {
+		return nil, errors.New("no certificates found")
+	}
+
+	return chain, nil
+}
+
+// FromConnectionState returns the certificate chain presented by the peer of a TLS connection
+func FromConnectionState(state tls.ConnectionState) []*x509.Certificate {
+	return state.PeerCertificates
+}
+
+// DaysThreshold returns a Threshold that is violated with less than the given days remaining,
+// e.g. DaysThreshold(30) equals ParseThreshold("30:")
+func DaysThreshold(days float64) *check.Threshold {
+	return &check.Threshold{Lower: days, Upper: check.PosInf}
+}
+
+// CheckExpiry evaluates the remaining validity of each certificate in the chain.
+//
+// The thresholds are compared against the remaining days, see DaysThreshold.
+// Expired and not yet valid certificates are always critical.
+// The returned PartialResult contains a subcheck with the remaining validity in seconds
+// as perfdata for every certificate.
+func CheckExpiry(chain []*x509.Certificate, warning, critical *check.Threshold) *result.PartialResult {
+	pr := result.NewPartialResult()
+
+	if len(chain) == 0 {
+		pr.SetOutput("No certificates found")
+		pr.SetState(check.Unknown)
+
+		return pr
+	}
+
+	pr.SetOutput("Certificate chain of " + chain[0].Subject.String())
+
+	now := time.Now()
+
+	for _, cert := range chain {
+		pr.AddSubcheck(checkCertificate(cert, now, warning, critical))
+	}
+
+	return pr
+}
+
+// checkCertificate evaluates the remaining validity of a single certificate
+func checkCertificate(cert *x509.Certificate, now time.Time, warning, critical *check.Threshold) *result.PartialResult {
+	pr := result.NewPartialResult()
+
+	remaining := cert.NotAfter.Sub(now)
+	days := remaining.Hours() / 24
+
+	label := cert.Subject.CommonName
+	if label == "" {
+		label = "serial_" + cert.SerialNumber.Text(16)
+	}
+
+	pr.AddPerfdata(&check.Perfdata{
+		Label: label,
+		Value: math.Round(remaining.Seconds()),
+		Uom:   "s",
+		Warn:  scaleThreshold(warning, secondsPerDay),
+		Crit:  scaleThreshold(critical, secondsPerDay),
+	})
+
+	description := fmt.Sprintf("%s issued by %s", cert.Subject, cert.Issuer)
+	expiry := cert.NotAfter.UTC().Format(time.RFC3339)
+
+	switch {
+	case now.Before(cert.NotBefore):
+		pr.SetState(check.Critical)
+		pr.SetOutput(fmt.Sprintf("%s is not valid before %s", description, cert.NotBefore.UTC().Format(time.RFC3339)))
+	case remaining < 0:
+		pr.SetState(check.Critical)
+		pr.SetOutput(fmt.Sprintf("%s expired %s days ago (%s)", description, formatDays(-days), expiry))
+	default:
+		switch {
+		case critical != nil && critical.DoesViolate(days):
+			pr.SetState(check.Critical)
+		case warning != nil && warning.DoesViolate(days):
+			pr.SetState(check.Warning)
+		default:
+			pr.SetState(check.OK)
+		}
+
+		pr.SetOutput(fmt.Sprintf("%s expires in %s days (%s)", description, formatDays(days), expiry))
+	}
+
+	return pr
+}
+
+// formatDays returns the number of full days
+func formatDays(days float64) string {
+	return strconv.FormatFloat(math.Floor(days), 'f', 0, 64)
+}
+
+// scaleThreshold returns a copy of the Threshold with both boundaries multiplied by factor
+func scaleThreshold(t *check.Threshold, factor float64) *check.Threshold {
+	if t == nil {
+		return nil
+	}
+
+	return &check.Threshold{
+		Inside: t.Inside,
+		Lower:  t.Lower * factor,
+		Upper:  t.Upper * factor,
+	}
+}
@@ -0,0 +1,163 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NETWAYS/go-check"
+	"github.com/NETWAYS/go-check/result"
+)
+
+// newTestCertificate generates a certificate, self-signed if no parent is given
+func newTestCertificate(t *testing.T, name string, notBefore, notAfter time.Time, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+	}
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	return cert, key
+}
+
+func days(n int) time.Time {
+	return time.Now().Add(time.Duration(n) * 24 * time.Hour)
+}
+
+func TestParsePEM(t *testing.T) {
+	ca, caKey := newTestCertificate(t, "Test CA", days(-1), days(100), nil, nil)
+	leaf, _ := newTestCertificate(t, "localhost", days(-1), days(10), ca, caKey)
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw})
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("skipped")})...)
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})...)
+
+	file := filepath.Join(t.TempDir(), "chain.pem")
+	_ = os.WriteFile(file, data, 0o600)
+
+	chain, err := LoadFile(file)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(chain) != 2 || chain[0].Subject.CommonName != "localhost" || chain[1].Subject.CommonName != "Test CA" {
+		t.Fatalf("expected chain of localhost and Test CA, got %v", chain)
+	}
+
+	_, err = ParsePEM([]byte("no certificate"))
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func TestCheckExpiry(t *testing.T) {
+	ca, caKey := newTestCertificate(t, "Test CA", days(-1), days(100), nil, nil)
+
+	testcases := map[string]struct {
+		notBefore time.Time
+		notAfter  time.Time
+		expected  check.Status
+		output    string
+	}{
+		"ok": {
+			notBefore: days(-1),
+			notAfter:  days(60),
+			expected:  check.OK,
+			output:    "CN=localhost issued by CN=Test CA expires in 59 days",
+		},
+		"warning": {
+			notBefore: days(-1),
+			notAfter:  days(20),
+			expected:  check.Warning,
+			output:    "expires in 19 days",
+		},
+		"critical": {
+			notBefore: days(-1),
+			notAfter:  days(3),
+			expected:  check.Critical,
+			output:    "expires in 2 days",
+		},
+		"expired": {
+			notBefore: days(-10),
+			notAfter:  days(-2),
+			expected:  check.Critical,
+			output:    "expired 2 days ago",
+		},
+		"not-yet-valid": {
+			notBefore: days(2),
+			notAfter:  days(60),
+			expected:  check.Critical,
+			output:    "is not valid before",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			leaf, _ := newTestCertificate(t, "localhost", tc.notBefore, tc.notAfter, ca, caKey)
+
+			state := tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf, ca}}
+
+			pr := CheckExpiry(FromConnectionState(state), DaysThreshold(30), DaysThreshold(7))
+
+			if pr.GetStatus() != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, pr.GetStatus())
+			}
+
+			var overall result.Overall
+
+			overall.AddSubcheck(pr)
+
+			output := overall.GetOutput()
+
+			if !strings.Contains(output, tc.output) {
+				t.Fatalf("expected %q in output, got %q", tc.output, output)
+			}
+
+			if !strings.Contains(output, "localhost=") || !strings.Contains(output, "s;2592000:;604800:") {
+				t.Fatalf("expected perfdata for localhost, got %q", output)
+			}
+		})
+	}
+}
+
+func TestCheckExpiry_WithoutCertificates(t *testing.T) {
+	pr := CheckExpiry(nil, nil, nil)
+
+	if pr.GetStatus() != check.Unknown {
+		t.Fatalf("expected %v, got %v", check.Unknown, pr.GetStatus())
+	}
+}
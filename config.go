@@ -2,6 +2,7 @@ package check
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"reflect"
@@ -9,6 +10,19 @@ import (
 	flag "github.com/spf13/pflag"
 )
 
+// Verbosity levels as described by the monitoring plugins guidelines,
+// see also: https://www.monitoring-plugins.org/doc/guidelines.html
+const (
+	// VerbositySummary shows a single line with the summary
+	VerbositySummary = 0
+	// VerbosityFailed additionally shows failed subchecks
+	VerbosityFailed = 1
+	// VerbosityAll additionally shows all subchecks
+	VerbosityAll = 2
+	// VerbosityDiagnostic additionally shows details for diagnosing problems
+	VerbosityDiagnostic = 3
+)
+
 // Config represents a configuration for a monitoring plugin's CLI
 type Config struct {
 	// Name of the monitoring plugin
@@ -19,8 +33,10 @@ type Config struct {
 	Version string
 	// Default for the --timeout flag
 	Timeout int
-	// Default for the --verbose flag
+	// Default for the --verbose flag, set to true when --verbose is given at least once
 	Verbose bool
+	// Verbosity level, counted by repeating the --verbose flag (e.g. -vvv)
+	Verbosity int
 	// Default for the --debug flag
 	Debug bool
 	// Enable predefined --version output
@@ -31,6 +47,22 @@ type Config struct {
 	DefaultHelper bool
 	// Additional CLI flags for the monitoring plugin
 	FlagSet *flag.FlagSet
+	// Output for the Logger, defaults to os.Stderr
+	LogOutput io.Writer
+	// Values (e.g. passwords or tokens) that are redacted from the Logger output
+	Secrets []string
+	// Values of flags (e.g. --password of AddAuthFlags) that are redacted from the Logger output
+	secretFlags []*string
+
+	// Hidden flags to generate documentation for packaging
+	generateMan        bool
+	generateMarkdown   bool
+	generateCompletion string
+	generateIcinga2    bool
+	generateDirector   bool
+
+	// Functions to validate flags after parsing, e.g. added by flag presets
+	validators []func() error
 }
 
 // NewConfig returns a Config struct with some defaults
@@ -79,11 +111,24 @@ func (c *Config) ParseArray(arguments []string) {
 		ExitError(err)
 	}
 
+	// Keep the boolean in sync with the counted --verbose flag
+	if c.Verbosity > 0 {
+		c.Verbose = true
+	}
+
+	if c.generateMan || c.generateMarkdown || c.generateCompletion != "" || c.generateIcinga2 || c.generateDirector {
+		c.generateDocs()
+	}
+
 	if c.PrintVersion {
 		fmt.Println(c.Name, "version", c.Version)
 		BaseExit(Unknown)
 	}
 
+	if err := c.validate(); err != nil {
+		ExitError(err)
+	}
+
 	if c.DefaultHelper {
 		c.EnableTimeoutHandler()
 	}
@@ -93,12 +138,59 @@ func (c *Config) ParseArray(arguments []string) {
 func (c *Config) addDefaultFlags() {
 	c.FlagSet.IntVarP(&c.Timeout, "timeout", "t", c.Timeout, "Abort the check after n seconds")
 	c.FlagSet.BoolVarP(&c.Debug, "debug", "d", false, "Enable debug mode")
-	c.FlagSet.BoolVarP(&c.Verbose, "verbose", "v", false, "Enable verbose mode")
+	c.FlagSet.CountVarP(&c.Verbosity, "verbose", "v", "Enable verbose mode, repeat for more details (e.g. -vvv)")
 	c.FlagSet.BoolVarP(&c.PrintVersion, "version", "V", false, "Print version and exit")
 
+	// Used for packaging, therefore not shown in the usage
+	c.FlagSet.BoolVar(&c.generateMan, "generate-man", false, "Print a man page and exit")
+	c.FlagSet.BoolVar(&c.generateMarkdown, "generate-markdown", false, "Print a Markdown reference and exit")
+	c.FlagSet.StringVar(&c.generateCompletion, "generate-completion", "", "Print a completion script for the given shell (bash, zsh, fish) and exit")
+	c.FlagSet.BoolVar(&c.generateIcinga2, "generate-icinga2", false, "Print an Icinga 2 CheckCommand definition and exit")
+	c.FlagSet.BoolVar(&c.generateDirector, "generate-director", false, "Print an Icinga Director basket and exit")
+
+	for _, name := range []string{"generate-man", "generate-markdown", "generate-completion", "generate-icinga2", "generate-director"} {
+		_ = c.FlagSet.MarkHidden(name)
+	}
+
 	c.DefaultFlags = false
 }
 
+// validate runs all registered validators and returns the first error
+func (c *Config) validate() error {
+	for _, validator := range c.validators {
+		if err := validator(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// generateDocs prints the requested documentation to stdout and exits with OK,
+// so packaging scripts can rely on the exit code
+func (c *Config) generateDocs() {
+	var err error
+
+	switch {
+	case c.generateMan:
+		err = c.GenerateManPage(os.Stdout)
+	case c.generateMarkdown:
+		err = c.GenerateMarkdown(os.Stdout)
+	case c.generateIcinga2:
+		err = c.GenerateCheckCommand(os.Stdout)
+	case c.generateDirector:
+		err = c.GenerateDirectorBasket(os.Stdout)
+	default:
+		err = c.GenerateCompletion(os.Stdout, c.generateCompletion)
+	}
+
+	if err != nil {
+		ExitError(err)
+	}
+
+	BaseExit(OK)
+}
+
 // LoadFromEnv can be used to load struct values from 'env' tags.
 // Mainly used to avoid passing secrets via the CLI
 //
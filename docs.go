@@ -0,0 +1,339 @@
+package check
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// Supported shells for GenerateCompletion
+const (
+	ShellBash = "bash"
+	ShellZsh  = "zsh"
+	ShellFish = "fish"
+)
+
+// Replace not allowed characters inside a shell function name
+var shellFunctionRe = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// flagDoc is the documented representation of a single flag
+type flagDoc struct {
+	Name      string
+	Shorthand string
+	// Type is the type of the flag value, e.g. string or stringSlice
+	Type string
+	// ValueName is the name of the expected value, empty if the flag takes no value
+	ValueName string
+	Usage     string
+	// Default is the default value, empty if it is the zero value
+	Default string
+	// Repeatable flags may be passed multiple times
+	Repeatable bool
+}
+
+// flagDocs returns the documentation for all visible flags of the FlagSet
+func (c *Config) flagDocs() []flagDoc {
+	var docs []flagDoc
+
+	c.FlagSet.VisitAll(func(f *flag.Flag) {
+		if f.Hidden {
+			return
+		}
+
+		valueName, usage := flag.UnquoteUsage(f)
+
+		doc := flagDoc{
+			Name:       f.Name,
+			Shorthand:  f.Shorthand,
+			Type:       f.Value.Type(),
+			Usage:      usage,
+			Repeatable: strings.HasSuffix(f.Value.Type(), "Slice") || strings.HasSuffix(f.Value.Type(), "Array") || f.Value.Type() == "count",
+		}
+
+		// Flags like bool and count don't expect a value
+		if f.NoOptDefVal == "" {
+			doc.ValueName = valueName
+		}
+
+		switch f.DefValue {
+		case "", "0", "0s", "false", "[]", "<nil>":
+		default:
+			doc.Default = f.DefValue
+		}
+
+		docs = append(docs, doc)
+	})
+
+	return docs
+}
+
+// description returns the first line of the Readme, used as short description
+func (c *Config) description() string {
+	line, _, _ := strings.Cut(strings.TrimSpace(c.Readme), "\n")
+
+	if line == "" {
+		return "Monitoring plugin"
+	}
+
+	return line
+}
+
+// GenerateManPage writes a roff formatted man page for the monitoring plugin
+func (c *Config) GenerateManPage(w io.Writer) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, ".TH %s 1 \"\" \"%s %s\" \"Monitoring Plugin\"\n",
+		roffEscape(strings.ToUpper(c.Name)), roffEscape(c.Name), roffEscape(c.Version))
+
+	sb.WriteString(".SH NAME\n")
+	sb.WriteString(roffEscape(c.Name) + " \\- " + roffEscape(c.description()) + "\n")
+
+	sb.WriteString(".SH SYNOPSIS\n")
+	sb.WriteString(".B " + roffEscape(c.Name) + "\n")
+	sb.WriteString("[\\fIOPTIONS\\fR]\n")
+
+	if c.Readme != "" {
+		sb.WriteString(".SH DESCRIPTION\n")
+
+		for _, line := range strings.Split(strings.TrimSpace(c.Readme), "\n") {
+			if strings.TrimSpace(line) == "" {
+				sb.WriteString(".PP\n")
+				continue
+			}
+
+			sb.WriteString(roffEscape(line) + "\n")
+		}
+	}
+
+	sb.WriteString(".SH OPTIONS\n")
+
+	for _, f := range c.flagDocs() {
+		sb.WriteString(".TP\n")
+
+		if f.Shorthand != "" {
+			sb.WriteString("\\fB\\-" + roffEscape(f.Shorthand) + "\\fR, ")
+		}
+
+		sb.WriteString("\\fB\\-\\-" + roffEscape(f.Name) + "\\fR")
+
+		if f.ValueName != "" {
+			sb.WriteString(" \\fI" + roffEscape(f.ValueName) + "\\fR")
+		}
+
+		sb.WriteString("\n" + roffEscape(f.Usage))
+
+		if f.Default != "" {
+			sb.WriteString(" (default: " + roffEscape(f.Default) + ")")
+		}
+
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(".SH EXIT STATUS\n")
+
+	for _, s := range []Status{OK, Warning, Critical, Unknown} {
+		fmt.Fprintf(&sb, ".TP\n.B %d\n%s\n", s, s)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// GenerateMarkdown writes a Markdown reference documentation for the monitoring plugin
+func (c *Config) GenerateMarkdown(w io.Writer) error {
+	var sb strings.Builder
+
+	sb.WriteString("# " + c.Name + "\n\n")
+
+	if c.Readme != "" {
+		sb.WriteString(strings.TrimSpace(c.Readme) + "\n\n")
+	}
+
+	if c.Version != "" {
+		sb.WriteString("Version: " + c.Version + "\n\n")
+	}
+
+	sb.WriteString("## Usage\n\n")
+	sb.WriteString("```\n" + c.Name + " [OPTIONS]\n```\n\n")
+
+	sb.WriteString("## Arguments\n\n")
+	sb.WriteString("| Flag | Value | Default | Description |\n")
+	sb.WriteString("|------|-------|---------|-------------|\n")
+
+	for _, f := range c.flagDocs() {
+		name := "`--" + f.Name + "`"
+		if f.Shorthand != "" {
+			name = "`-" + f.Shorthand + "`, " + name
+		}
+
+		def := ""
+		if f.Default != "" {
+			def = "`" + f.Default + "`"
+		}
+
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n",
+			name, markdownEscape(f.ValueName), markdownEscape(def), markdownEscape(f.Usage))
+	}
+
+	sb.WriteString("\n## Exit Codes\n\n")
+
+	for _, s := range []Status{OK, Warning, Critical, Unknown} {
+		fmt.Fprintf(&sb, "* %d - %s\n", s, s)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// GenerateCompletion writes a completion script for the given shell (bash, zsh or fish)
+func (c *Config) GenerateCompletion(w io.Writer, shell string) error {
+	var script string
+
+	switch shell {
+	case ShellBash:
+		script = c.bashCompletion()
+	case ShellZsh:
+		script = c.zshCompletion()
+	case ShellFish:
+		script = c.fishCompletion()
+	default:
+		return fmt.Errorf("unsupported shell for completion: %s", shell)
+	}
+
+	_, err := io.WriteString(w, script)
+
+	return err
+}
+
+func (c *Config) bashCompletion() string {
+	var (
+		sb         strings.Builder
+		words      []string
+		withValues []string
+	)
+
+	for _, f := range c.flagDocs() {
+		names := []string{"--" + f.Name}
+		if f.Shorthand != "" {
+			names = append(names, "-"+f.Shorthand)
+		}
+
+		words = append(words, names...)
+
+		if f.ValueName != "" {
+			withValues = append(withValues, names...)
+		}
+	}
+
+	function := "_" + shellFunctionRe.ReplaceAllString(c.Name, "_")
+
+	sb.WriteString("# bash completion for " + c.Name + "\n")
+	sb.WriteString(function + "() {\n")
+	sb.WriteString("\tlocal cur prev\n")
+	sb.WriteString("\tcur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	sb.WriteString("\tprev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n\n")
+
+	if len(withValues) > 0 {
+		sb.WriteString("\t# Fall back to the default completion for flag values\n")
+		sb.WriteString("\tcase \"${prev}\" in\n")
+		sb.WriteString("\t\t" + strings.Join(withValues, "|") + ")\n")
+		sb.WriteString("\t\t\treturn 0\n")
+		sb.WriteString("\t\t\t;;\n")
+		sb.WriteString("\tesac\n\n")
+	}
+
+	sb.WriteString("\tCOMPREPLY=($(compgen -W \"" + strings.Join(words, " ") + "\" -- \"${cur}\"))\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("complete -o default -F " + function + " " + c.Name + "\n")
+
+	return sb.String()
+}
+
+func (c *Config) zshCompletion() string {
+	var sb strings.Builder
+
+	sb.WriteString("#compdef " + c.Name + "\n\n")
+	sb.WriteString("_arguments -s")
+
+	for _, f := range c.flagDocs() {
+		spec := "'[" + zshEscape(f.Usage) + "]"
+
+		if f.ValueName != "" {
+			spec += ":" + zshEscape(f.ValueName) + ":"
+		}
+
+		spec += "'"
+
+		var names string
+
+		if f.Shorthand != "" {
+			names = "{-" + f.Shorthand + ",--" + f.Name + "}"
+
+			if !f.Repeatable {
+				names = "'(-" + f.Shorthand + " --" + f.Name + ")'" + names
+			}
+		} else {
+			names = "--" + f.Name
+		}
+
+		if f.Repeatable {
+			names = "'*'" + names
+		}
+
+		sb.WriteString(" \\\n\t" + names + spec)
+	}
+
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+func (c *Config) fishCompletion() string {
+	var sb strings.Builder
+
+	sb.WriteString("# fish completion for " + c.Name + "\n")
+
+	for _, f := range c.flagDocs() {
+		sb.WriteString("complete -c " + c.Name)
+
+		if f.Shorthand != "" {
+			sb.WriteString(" -s " + f.Shorthand)
+		}
+
+		sb.WriteString(" -l " + f.Name)
+
+		if f.ValueName != "" {
+			sb.WriteString(" -r")
+		}
+
+		sb.WriteString(" -d '" + strings.ReplaceAll(strings.ReplaceAll(f.Usage, `\`, `\\`), `'`, `\'`) + "'\n")
+	}
+
+	return sb.String()
+}
+
+// roffEscape escapes characters with a special meaning in roff
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+
+	// Lines starting with a dot or an apostrophe would be interpreted as requests
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+
+	return s
+}
+
+// markdownEscape escapes characters that would break a Markdown table
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
+
+// zshEscape escapes characters with a special meaning inside a quoted _arguments spec
+var zshEscape = strings.NewReplacer(`'`, `'\''`, `[`, `\[`, `]`, `\]`, `:`, `\:`).Replace
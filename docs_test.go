@@ -0,0 +1,119 @@
+package check
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func newDocsConfig() *Config {
+	c := NewConfig()
+	c.Name = "check_test"
+	c.Readme = "Test Plugin\n\nChecks the test service"
+	c.Version = "1.0.0"
+	c.DefaultHelper = false
+
+	_ = c.FlagSet.StringP("hostname", "H", "localhost", "Hostname to check")
+	_ = c.FlagSet.Bool("insecure", false, "Skip the TLS verification")
+
+	c.addDefaultFlags()
+
+	return c
+}
+
+func TestConfig_GenerateManPage(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := newDocsConfig().GenerateManPage(&buf)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{
+		".TH CHECK_TEST 1 \"\" \"check_test 1.0.0\" \"Monitoring Plugin\"\n",
+		"check_test \\- Test Plugin\n",
+		".TP\n\\fB\\-H\\fR, \\fB\\-\\-hostname\\fR \\fIstring\\fR\nHostname to check (default: localhost)\n",
+		".TP\n\\fB\\-\\-insecure\\fR\nSkip the TLS verification\n",
+	}
+
+	for _, e := range expected {
+		if !strings.Contains(buf.String(), e) {
+			t.Fatalf("expected %q in output, got %q", e, buf.String())
+		}
+	}
+
+	if strings.Contains(buf.String(), "generate") {
+		t.Fatalf("expected no hidden flags in output, got %q", buf.String())
+	}
+}
+
+func TestConfig_GenerateMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := newDocsConfig().GenerateMarkdown(&buf)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "| `-H`, `--hostname` | string | `localhost` | Hostname to check |\n"
+
+	if !strings.Contains(buf.String(), expected) {
+		t.Fatalf("expected %q in output, got %q", expected, buf.String())
+	}
+}
+
+func TestConfig_GenerateCompletion(t *testing.T) {
+	testcases := map[string]struct {
+		shell    string
+		expected []string
+	}{
+		"bash": {
+			shell: ShellBash,
+			expected: []string{
+				"--hostname|-H|--timeout|-t)",
+				"complete -o default -F _check_test check_test\n",
+			},
+		},
+		"zsh": {
+			shell: ShellZsh,
+			expected: []string{
+				"#compdef check_test\n",
+				"'(-H --hostname)'{-H,--hostname}'[Hostname to check]:string:'",
+				"'*'{-v,--verbose}'[Enable verbose mode, repeat for more details (e.g. -vvv)]'",
+			},
+		},
+		"fish": {
+			shell: ShellFish,
+			expected: []string{
+				"complete -c check_test -s H -l hostname -r -d 'Hostname to check'\n",
+				"complete -c check_test -l insecure -d 'Skip the TLS verification'\n",
+			},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := newDocsConfig().GenerateCompletion(&buf, tc.shell)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			for _, e := range tc.expected {
+				if !strings.Contains(buf.String(), e) {
+					t.Fatalf("expected %q in output, got %q", e, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestConfig_GenerateCompletion_WithErr(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := newDocsConfig().GenerateCompletion(&buf, "powershell")
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"log"
 
 	"github.com/NETWAYS/go-check"
 )
@@ -21,9 +20,8 @@ func main() {
 
 	config.ParseArguments()
 
-	if config.Debug {
-		log.Println("Start logging")
-	}
+	logger := config.Logger()
+	logger.Debug("Start logging", "value", *value)
 	// time.Sleep(20 * time.Second)
 
 	if *value > *critical {
@@ -43,11 +43,21 @@ func Exit(rc Status, output ...string) {
 // the performance data and the text output to stdout.
 //
 // The provided text output will be sanitized to avoid multiple performance data
-// separators (|).
+// separators (|). The output is limited to MaxOutputLength, see TruncateOutput.
+//
+// If InvalidValues is InvalidValueFail and any perfdata has a NaN or infinite value,
+// the state is UNKNOWN and the errors are shown before the output.
 //
 // Example: [OK] - everything is fine | mylabel=1
 // exit 0
 func ExitWithPerfdata(rc Status, perfdata PerfdataList, output ...string) {
+	if InvalidValues == InvalidValueFail {
+		if err := perfdata.invalidValues(); err != nil {
+			rc = Unknown
+			output = append([]string{strings.ReplaceAll(err.Error(), "\n", ", ") + " -"}, output...)
+		}
+	}
+
 	var text strings.Builder
 
 	text.WriteString("[" + rc.String() + "] -")
@@ -56,13 +66,14 @@ func ExitWithPerfdata(rc Status, perfdata PerfdataList, output ...string) {
 		text.WriteString(" " + strings.ReplaceAll(s, PerfdataSeparatorSymbol, " "))
 	}
 
-	text.WriteString(PerfdataSeparatorSymbol)
+	out, pdata := text.String(), perfdata.String()
 
-	text.WriteString(perfdata.String())
-
-	text.WriteString("\n")
+	if MaxOutputLength > 0 {
+		// Leave space for the separator and the line break, but truncate for very small limits as well
+		out, pdata = TruncateOutput(out, pdata, max(MaxOutputLength-2, 1))
+	}
 
-	_, _ = os.Stdout.WriteString(text.String())
+	_, _ = os.Stdout.WriteString(out + PerfdataSeparatorSymbol + pdata + "\n")
 
 	BaseExit(rc)
 }
@@ -2,6 +2,7 @@ package check
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"testing"
 )
@@ -22,6 +23,22 @@ func ExampleExitWithPerfdata() {
 	// would exit with code 2
 }
 
+func ExampleExitWithPerfdata_invalidValues() {
+	InvalidValues = InvalidValueFail
+
+	defer func() {
+		InvalidValues = InvalidValueDrop
+	}()
+
+	perfdata := PerfdataList{}
+	perfdata.Add(&Perfdata{Label: "load", Value: math.NaN()})
+	perfdata.Add(&Perfdata{Label: "packages_lost", Value: 42})
+
+	ExitWithPerfdata(OK, perfdata, "Everything is fine")
+	// Output: [UNKNOWN] - invalid perfdata value for load: perfdata value is not a number - Everything is fine|packages_lost=42
+	// would exit with code 3
+}
+
 func ExampleExitError() {
 	err := fmt.Errorf("connection to %s has been timed out", "localhost:12345")
 	ExitError(err)
@@ -46,3 +63,35 @@ func TestMain(m *testing.M) {
 
 	os.Exit(m.Run())
 }
+
+func ExampleExitWithPerfdata_maxOutputLength() {
+	MaxOutputLength = 80
+
+	defer func() {
+		MaxOutputLength = 0
+	}()
+
+	perfdata := PerfdataList{}
+	perfdata.Add(&Perfdata{Label: "time_duration", Value: 23})
+
+	ExitWithPerfdata(Critical, perfdata, "Everything is broken\nline 1 with details\nline 2 with details")
+	// Output: [CRITICAL] - Everything is broken
+	// (output truncated)|time_duration=23
+	// would exit with code 2
+}
+
+func ExampleExitWithPerfdata_smallMaxOutputLength() {
+	// The separator and the line break are always written
+	MaxOutputLength = 3
+
+	defer func() {
+		MaxOutputLength = 0
+	}()
+
+	perfdata := PerfdataList{}
+	perfdata.Add(&Perfdata{Label: "time_duration", Value: 23})
+
+	ExitWithPerfdata(Critical, perfdata, "Everything is broken")
+	// Output: (|
+	// would exit with code 2
+}
@@ -0,0 +1,93 @@
+package check
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FilterOptions contains the expressions of the flags registered by AddFilterFlags
+type FilterOptions struct {
+	Include []*regexp.Regexp
+	Exclude []*regexp.Regexp
+}
+
+// AddFilterFlags registers the --include, --exclude and --glob flags, e.g. to select disks or interfaces.
+//
+// --include and --exclude can be passed multiple times with a regular expression,
+// or with a glob pattern (* and ?) if --glob is set.
+// The expressions can be used for a result.Filter:
+//
+//	overall.ApplyFilter(result.Filter{Include: filter.Include, Exclude: filter.Exclude})
+func (c *Config) AddFilterFlags() *FilterOptions {
+	o := &FilterOptions{}
+
+	var (
+		include []string
+		exclude []string
+		glob    bool
+	)
+
+	c.FlagSet.StringArrayVar(&include, "include", nil, "Only include subchecks with a matching name (regular expression)")
+	c.FlagSet.StringArrayVar(&exclude, "exclude", nil, "Exclude subchecks with a matching name (regular expression)")
+	c.FlagSet.BoolVar(&glob, "glob", false, "Use glob patterns instead of regular expressions for --include and --exclude")
+
+	c.validators = append(c.validators, func() error {
+		var err error
+
+		if o.Include, err = compileFilter(include, glob); err != nil {
+			return fmt.Errorf("invalid value for --include: %w", err)
+		}
+
+		if o.Exclude, err = compileFilter(exclude, glob); err != nil {
+			return fmt.Errorf("invalid value for --exclude: %w", err)
+		}
+
+		return nil
+	})
+
+	return o
+}
+
+// compileFilter compiles the patterns as regular expressions or glob patterns
+func compileFilter(patterns []string, glob bool) ([]*regexp.Regexp, error) {
+	expressions := make([]*regexp.Regexp, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		if glob {
+			pattern = globToRegexp(pattern)
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		expressions = append(expressions, re)
+	}
+
+	return expressions, nil
+}
+
+// globToRegexp converts a glob pattern to an anchored regular expression.
+// * matches any sequence of characters and ? a single character.
+func globToRegexp(pattern string) string {
+	var sb strings.Builder
+
+	sb.WriteString("^")
+
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	sb.WriteString("$")
+
+	return sb.String()
+}
@@ -0,0 +1,60 @@
+package check
+
+import (
+	"testing"
+)
+
+func TestConfig_AddFilterFlags(t *testing.T) {
+	testcases := map[string]struct {
+		args     []string
+		name     string
+		included bool
+		excluded bool
+	}{
+		"regexp": {
+			args:     []string{"--include", "^eth", "--exclude", "eth1$"},
+			name:     "eth1",
+			included: true,
+			excluded: true,
+		},
+		"glob": {
+			args:     []string{"--glob", "--include", "/dev/sd?", "--exclude", "/boot*"},
+			name:     "/dev/sda",
+			included: true,
+			excluded: false,
+		},
+		"glob-anchored": {
+			args:     []string{"--glob", "--include", "sd?"},
+			name:     "/dev/sda",
+			included: false,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			c := newPresetConfig()
+			filter := c.AddFilterFlags()
+
+			c.ParseArray(tc.args)
+
+			if filter.Include[0].MatchString(tc.name) != tc.included {
+				t.Fatalf("expected include %v, got %v", tc.included, !tc.included)
+			}
+
+			if len(filter.Exclude) > 0 && filter.Exclude[0].MatchString(tc.name) != tc.excluded {
+				t.Fatalf("expected exclude %v, got %v", tc.excluded, !tc.excluded)
+			}
+		})
+	}
+}
+
+func TestConfig_AddFilterFlags_WithErr(t *testing.T) {
+	c := newPresetConfig()
+	_ = c.AddFilterFlags()
+
+	_ = c.FlagSet.Parse([]string{"--include", "["})
+
+	if c.validate() == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
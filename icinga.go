@@ -0,0 +1,276 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Prefixes of the Icinga Director data types
+const directorDataType = `Icinga\Module\Director\DataType\DataType`
+
+// Flags which are mapped to the address of the host by default
+var icingaAddressFlags = []string{"hostname", "host", "address"}
+
+// icingaArgument is the mapping of a single flag to an Icinga 2 custom variable
+type icingaArgument struct {
+	Key         string
+	Var         string
+	Description string
+	// SetIf is used for flags without a value, the flag is only passed if the variable is true
+	SetIf bool
+	// RepeatKey is used for flags that can be passed multiple times with an array
+	RepeatKey bool
+	// Default is the Icinga 2 DSL representation of the default, empty if there is none
+	Default string
+	// DefaultValue is the default as JSON compatible value, nil if there is none
+	DefaultValue any
+	DataType     string
+}
+
+// icingaVarPrefix returns the prefix for custom variables, e.g. "http" for "check_http"
+func (c *Config) icingaVarPrefix() string {
+	return shellFunctionRe.ReplaceAllString(strings.TrimPrefix(c.Name, "check_"), "_")
+}
+
+// icingaArguments returns the Icinga 2 argument mapping for all visible flags of the FlagSet
+func (c *Config) icingaArguments() []icingaArgument {
+	var args []icingaArgument
+
+	prefix := c.icingaVarPrefix()
+
+	for _, f := range c.flagDocs() {
+		// No use in a CheckCommand
+		if f.Name == "version" {
+			continue
+		}
+
+		arg := icingaArgument{
+			Key:         "--" + f.Name,
+			Var:         prefix + "_" + strings.ReplaceAll(f.Name, "-", "_"),
+			Description: f.Usage,
+			SetIf:       f.ValueName == "",
+			RepeatKey:   f.ValueName != "" && f.Repeatable,
+		}
+
+		switch {
+		case arg.SetIf:
+			arg.DataType = directorDataType + "Boolean"
+		case arg.RepeatKey:
+			arg.DataType = directorDataType + "Array"
+		case isNumericFlagType(f.Type):
+			arg.DataType = directorDataType + "Number"
+		default:
+			arg.DataType = directorDataType + "String"
+		}
+
+		if f.Default != "" && !arg.SetIf {
+			arg.Default, arg.DefaultValue = icingaValue(f)
+		}
+
+		for _, name := range icingaAddressFlags {
+			if f.Name == name {
+				arg.Default, arg.DefaultValue = icingaString("$address$"), "$address$"
+			}
+		}
+
+		args = append(args, arg)
+	}
+
+	return args
+}
+
+// isNumericFlagType returns true for flag types that can be represented as number in Icinga 2
+func isNumericFlagType(t string) bool {
+	switch t {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return true
+	}
+
+	return false
+}
+
+// icingaValue returns the default of a flag in the Icinga 2 DSL and as JSON compatible value
+func icingaValue(f flagDoc) (string, any) {
+	if f.Repeatable {
+		items := strings.Split(strings.Trim(f.Default, "[]"), ",")
+		quoted := make([]string, 0, len(items))
+
+		for _, item := range items {
+			quoted = append(quoted, icingaString(icingaEscapeMacro(item)))
+		}
+
+		return "[ " + strings.Join(quoted, ", ") + " ]", items
+	}
+
+	if isNumericFlagType(f.Type) {
+		return f.Default, json.Number(f.Default)
+	}
+
+	return icingaString(icingaEscapeMacro(f.Default)), f.Default
+}
+
+// icingaEscapeMacro escapes dollar signs, so they are not resolved as runtime macros
+func icingaEscapeMacro(s string) string {
+	return strings.ReplaceAll(s, "$", "$$")
+}
+
+// icingaString returns a quoted string for the Icinga 2 DSL
+func icingaString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// GenerateCheckCommand writes an Icinga 2 CheckCommand definition for the monitoring plugin
+//
+// Each flag is mapped to a custom variable prefixed with the plugin name,
+// e.g. --hostname of check_http is mapped to $http_hostname$.
+func (c *Config) GenerateCheckCommand(w io.Writer) error {
+	var (
+		sb   strings.Builder
+		vars strings.Builder
+	)
+
+	sb.WriteString("object CheckCommand " + icingaString(c.Name) + " {\n")
+	sb.WriteString("\tcommand = [ PluginDir + " + icingaString("/"+c.Name) + " ]\n\n")
+	sb.WriteString("\targuments = {\n")
+
+	for _, arg := range c.icingaArguments() {
+		sb.WriteString("\t\t" + icingaString(arg.Key) + " = {\n")
+
+		if arg.SetIf {
+			sb.WriteString("\t\t\tset_if = \"$" + arg.Var + "$\"\n")
+		} else {
+			sb.WriteString("\t\t\tvalue = \"$" + arg.Var + "$\"\n")
+		}
+
+		if arg.RepeatKey {
+			sb.WriteString("\t\t\trepeat_key = true\n")
+		}
+
+		sb.WriteString("\t\t\tdescription = " + icingaString(arg.Description) + "\n")
+		sb.WriteString("\t\t}\n")
+
+		if arg.Default != "" {
+			vars.WriteString("\tvars." + arg.Var + " = " + arg.Default + "\n")
+		}
+	}
+
+	sb.WriteString("\t}\n")
+
+	if vars.Len() > 0 {
+		sb.WriteString("\n" + vars.String())
+	}
+
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// directorBasket represents the JSON format of an Icinga Director configuration basket
+type directorBasket struct {
+	Command   map[string]directorCommand   `json:"Command"`
+	Datafield map[string]directorDatafield `json:"Datafield"`
+}
+
+type directorCommand struct {
+	Arguments      map[string]directorArgument `json:"arguments"`
+	Command        string                      `json:"command"`
+	Disabled       bool                        `json:"disabled"`
+	Fields         []directorField             `json:"fields"`
+	MethodsExecute string                      `json:"methods_execute"`
+	ObjectName     string                      `json:"object_name"`
+	ObjectType     string                      `json:"object_type"`
+	Vars           map[string]any              `json:"vars"`
+}
+
+type directorArgument struct {
+	Description string `json:"description,omitempty"`
+	Value       string `json:"value,omitempty"`
+	SetIf       string `json:"set_if,omitempty"`
+	SetIfFormat string `json:"set_if_format,omitempty"`
+	RepeatKey   bool   `json:"repeat_key,omitempty"`
+}
+
+type directorField struct {
+	DatafieldID int    `json:"datafield_id"`
+	IsRequired  string `json:"is_required"`
+	VarFilter   any    `json:"var_filter"`
+}
+
+type directorDatafield struct {
+	Varname     string         `json:"varname"`
+	Caption     string         `json:"caption"`
+	Description string         `json:"description"`
+	Datatype    string         `json:"datatype"`
+	Format      any            `json:"format"`
+	OriginalID  string         `json:"originalId"`
+	Settings    map[string]any `json:"settings"`
+}
+
+// GenerateDirectorBasket writes an Icinga Director configuration basket (JSON)
+// containing the CheckCommand and a data field for every flag.
+func (c *Config) GenerateDirectorBasket(w io.Writer) error {
+	command := directorCommand{
+		Arguments:      map[string]directorArgument{},
+		Command:        c.Name,
+		MethodsExecute: "PluginCheck",
+		ObjectName:     c.Name,
+		ObjectType:     "object",
+		Vars:           map[string]any{},
+	}
+
+	basket := directorBasket{
+		Command:   map[string]directorCommand{},
+		Datafield: map[string]directorDatafield{},
+	}
+
+	for i, arg := range c.icingaArguments() {
+		id := i + 1
+
+		argument := directorArgument{
+			Description: arg.Description,
+			RepeatKey:   arg.RepeatKey,
+		}
+
+		if arg.SetIf {
+			argument.SetIf = "$" + arg.Var + "$"
+			argument.SetIfFormat = "string"
+		} else {
+			argument.Value = "$" + arg.Var + "$"
+		}
+
+		command.Arguments[arg.Key] = argument
+
+		command.Fields = append(command.Fields, directorField{
+			DatafieldID: id,
+			IsRequired:  "n",
+		})
+
+		if arg.DefaultValue != nil {
+			command.Vars[arg.Var] = arg.DefaultValue
+		}
+
+		basket.Datafield[fmt.Sprint(id)] = directorDatafield{
+			Varname:     arg.Var,
+			Caption:     strings.TrimPrefix(arg.Key, "--"),
+			Description: arg.Description,
+			Datatype:    arg.DataType,
+			OriginalID:  fmt.Sprint(id),
+			Settings:    map[string]any{},
+		}
+	}
+
+	basket.Command[c.Name] = command
+
+	data, err := json.MarshalIndent(basket, "", "    ")
+	if err != nil {
+		return fmt.Errorf("could not encode director basket: %w", err)
+	}
+
+	_, err = w.Write(append(data, '\n'))
+
+	return err
+}
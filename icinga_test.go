@@ -0,0 +1,77 @@
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestConfig_GenerateCheckCommand(t *testing.T) {
+	var buf bytes.Buffer
+
+	c := newDocsConfig()
+	_ = c.FlagSet.StringSlice("exclude", []string{"tmpfs"}, "Exclude a filesystem type")
+
+	err := c.GenerateCheckCommand(&buf)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"object CheckCommand \"check_test\" {\n\tcommand = [ PluginDir + \"/check_test\" ]\n",
+		"\t\t\"--hostname\" = {\n\t\t\tvalue = \"$test_hostname$\"\n\t\t\tdescription = \"Hostname to check\"\n\t\t}\n",
+		"\t\t\"--insecure\" = {\n\t\t\tset_if = \"$test_insecure$\"\n",
+		"\t\t\"--exclude\" = {\n\t\t\tvalue = \"$test_exclude$\"\n\t\t\trepeat_key = true\n",
+		"\tvars.test_hostname = \"$address$\"\n",
+		"\tvars.test_timeout = 30\n",
+		"\tvars.test_exclude = [ \"tmpfs\" ]\n",
+	}
+
+	for _, e := range expected {
+		if !strings.Contains(buf.String(), e) {
+			t.Fatalf("expected %q in output, got %q", e, buf.String())
+		}
+	}
+
+	if strings.Contains(buf.String(), "--version") {
+		t.Fatalf("expected no --version argument, got %q", buf.String())
+	}
+}
+
+func TestConfig_GenerateDirectorBasket(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := newDocsConfig().GenerateDirectorBasket(&buf)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var basket directorBasket
+
+	err = json.Unmarshal(buf.Bytes(), &basket)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	command, ok := basket.Command["check_test"]
+	if !ok {
+		t.Fatalf("expected command check_test, got %v", basket.Command)
+	}
+
+	if command.Arguments["--insecure"].SetIf != "$test_insecure$" {
+		t.Fatalf("expected %v, got %v", "$test_insecure$", command.Arguments["--insecure"].SetIf)
+	}
+
+	if command.Vars["test_hostname"] != "$address$" {
+		t.Fatalf("expected %v, got %v", "$address$", command.Vars["test_hostname"])
+	}
+
+	if len(command.Fields) != len(basket.Datafield) {
+		t.Fatalf("expected %d datafields, got %d", len(command.Fields), len(basket.Datafield))
+	}
+
+	if basket.Datafield["1"].Varname != "test_hostname" {
+		t.Fatalf("expected %v, got %v", "test_hostname", basket.Datafield["1"].Varname)
+	}
+}
@@ -0,0 +1,123 @@
+package check
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// RedactedValue replaces secrets in the output of the Logger
+const RedactedValue = "[REDACTED]"
+
+// sensitiveKeys lists parts of attribute keys whose values are always redacted
+var sensitiveKeys = []string{"password", "passwd", "secret", "token", "apikey", "api_key", "authorization", "credential"}
+
+// LogLevel returns the log level derived from the --debug and --verbose flags
+//
+//	default  warnings and errors
+//	-v       informational messages
+//	-vv      debug messages
+//	-vvv     debug messages including the source location
+//
+// --debug always enables debug messages.
+func (c *Config) LogLevel() slog.Level {
+	switch {
+	case c.Debug || c.Verbosity >= 2:
+		return slog.LevelDebug
+	case c.Verbosity == 1 || c.Verbose:
+		return slog.LevelInfo
+	default:
+		return slog.LevelWarn
+	}
+}
+
+// Logger returns a structured logger for the monitoring plugin.
+//
+// The logger writes to LogOutput, which defaults to stderr, so log messages never mix
+// with the plugin output on stdout. Attributes with a sensitive key (e.g. "password" or "token")
+// and all values listed in Secrets at the time of the call are redacted.
+// Passwords of flag presets (e.g. AddAuthFlags) are redacted as well, the Logger may be created before
+// or after the arguments are parsed.
+//
+// To attach the log messages to the long output of a result.Overall use an io.MultiWriter:
+//
+//	config.LogOutput = io.MultiWriter(os.Stderr, overall.DiagnosticWriter())
+//	logger := config.Logger()
+func (c *Config) Logger() *slog.Logger {
+	var output io.Writer = os.Stderr
+	if c.LogOutput != nil {
+		output = c.LogOutput
+	}
+
+	secrets := make([]string, 0, len(c.Secrets))
+
+	for _, s := range c.Secrets {
+		if s != "" {
+			secrets = append(secrets, s)
+		}
+	}
+
+	opts := &slog.HandlerOptions{
+		AddSource:   c.Verbosity >= 3,
+		Level:       c.LogLevel(),
+		ReplaceAttr: redactAttr(secrets, c.secretFlags),
+	}
+
+	return slog.New(slog.NewTextHandler(output, opts))
+}
+
+// redactAttr returns a slog ReplaceAttr function, which removes the timestamp
+// and redacts sensitive attributes, the given secrets and the values of the secret flags when logging
+func redactAttr(secrets []string, secretFlags []*string) func(groups []string, a slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		// Timestamps are added by the monitoring system anyway
+		if len(groups) == 0 && a.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+
+		key := strings.ToLower(a.Key)
+
+		for _, sensitive := range sensitiveKeys {
+			if strings.Contains(key, sensitive) {
+				return slog.String(a.Key, RedactedValue)
+			}
+		}
+
+		a.Value = a.Value.Resolve()
+
+		// Flags are read when logging, since they might not be parsed when the Logger is created.
+		// The capacity is limited, so the shared slice is copied on append.
+		secrets := secrets[:len(secrets):len(secrets)]
+
+		for _, flag := range secretFlags {
+			if *flag != "" {
+				secrets = append(secrets, *flag)
+			}
+		}
+
+		switch a.Value.Kind() {
+		case slog.KindString:
+			a.Value = slog.StringValue(redactSecrets(a.Value.String(), secrets))
+		case slog.KindAny:
+			// Errors and fmt.Stringers (e.g. URLs) are formatted, since they often contain credentials
+			formatted := fmt.Sprintf("%+v", a.Value.Any())
+
+			if redacted := redactSecrets(formatted, secrets); redacted != formatted {
+				a.Value = slog.StringValue(redacted)
+			}
+		}
+
+		return a
+	}
+}
+
+// redactSecrets replaces all secrets in the value with the RedactedValue
+func redactSecrets(value string, secrets []string) string {
+	for _, secret := range secrets {
+		value = strings.ReplaceAll(value, secret, RedactedValue)
+	}
+
+	return value
+}
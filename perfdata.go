@@ -1,14 +1,23 @@
 package check
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const PerfdataSeparatorSymbol = "|"
 
+// PerfdataCheckSeparator separates the check and the label in the Icinga multi-check notation, e.g. 'disk::usage'=42%
+const PerfdataCheckSeparator = "::"
+
 // PerfdataList can store multiple perfdata and implements the fmt.Stringer interface
 // to provide formatted output for the performance data
 type PerfdataList []*Perfdata
@@ -35,43 +44,167 @@ func (l *PerfdataList) Add(p *Perfdata) {
 	*l = append(*l, p)
 }
 
+// PerfdataUnknownValue is rendered for values that could not be determined, see UnknownValue
+const PerfdataUnknownValue = "U"
+
+// UnknownValue can be used as value of Perfdata that could not be determined.
+// It is rendered as "U" without the unit of measurement, e.g. time=U;1;2
+type UnknownValue struct{}
+
+// String returns PerfdataUnknownValue
+func (UnknownValue) String() string {
+	return PerfdataUnknownValue
+}
+
+var (
+	// ErrPerfdataNaN is returned for perfdata values that are not a number
+	ErrPerfdataNaN = errors.New("perfdata value is not a number")
+	// ErrPerfdataInf is returned for infinite perfdata values
+	ErrPerfdataInf = errors.New("perfdata value is infinite")
+)
+
+// InvalidValuePolicy defines how Perfdata with a NaN or infinite value is rendered, see InvalidValues
+type InvalidValuePolicy int
+
+const (
+	// InvalidValueDrop omits the perfdata point, this is the default
+	InvalidValueDrop InvalidValuePolicy = iota
+	// InvalidValueUnknown renders the value as PerfdataUnknownValue
+	InvalidValueUnknown
+	// InvalidValueFail omits the perfdata point and sets the state to UNKNOWN,
+	// for a result.Overall and ExitWithPerfdata
+	InvalidValueFail
+)
+
+// InvalidValues is the policy for Perfdata with a NaN or infinite value
+var InvalidValues = InvalidValueDrop
+
+// IsInvalidValue returns true if the error is caused by a NaN or infinite perfdata value
+func IsInvalidValue(err error) bool {
+	return errors.Is(err, ErrPerfdataNaN) || errors.Is(err, ErrPerfdataInf)
+}
+
+// invalidValues returns the errors of all perfdata points with a NaN or infinite value
+func (l PerfdataList) invalidValues() error {
+	var errs []error
+
+	for _, p := range l {
+		if _, err := p.ValidatedString(); IsInvalidValue(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkFloat returns an error for NaN and infinite values
+func checkFloat(value float64) error {
+	if math.IsNaN(value) {
+		return ErrPerfdataNaN
+	}
+
+	if math.IsInf(value, 0) {
+		return ErrPerfdataInf
+	}
+
+	return nil
+}
+
 // Replace not allowed characters inside a label
 var replacer = strings.NewReplacer("=", "_", "`", "_", "'", "_", "\"", "_")
 
 // formatNumeric returns a string representation of various possible numerics
 //
-// This supports most internal types of Go and all fmt.Stringer interfaces.
+// This supports most internal types of Go, custom types based on them, *big.Int, *big.Float, json.Number
+// and all fmt.Stringer interfaces returning a number. A time.Duration is formatted in seconds.
 // Returns an error in some known cases where the value of a data type does not
-// represent a valid measurement, e.g INF for floats
+// represent a valid measurement, e.g ErrPerfdataInf for floats, or the type is not supported.
 // This error can probably ignored in most cases and the perfdata point omitted,
 // but silently dropping the value and returning the empty strings seems like bad style
 func formatNumeric(value any) (string, error) {
 	switch v := value.(type) {
 	case float64:
-		if math.IsInf(v, 0) {
-			return "", errors.New("Perfdata value is infinite")
-		}
-
-		if math.IsNaN(v) {
-			return "", errors.New("Perfdata value is infinite")
+		if err := checkFloat(v); err != nil {
+			return "", err
 		}
 
 		return FormatFloat(v), nil
 	case float32:
-		if math.IsInf(float64(v), 0) {
-			return "", errors.New("Perfdata value is infinite")
-		}
-
-		if math.IsNaN(float64(v)) {
-			return "", errors.New("Perfdata value is infinite")
+		if err := checkFloat(float64(v)); err != nil {
+			return "", err
 		}
 
 		return FormatFloat(float64(v)), nil
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
 		return fmt.Sprintf("%d", v), nil
-	default:
-		panic(fmt.Sprintf("unsupported type for perfdata: %T", value))
+	case time.Duration:
+		// Durations are formatted in seconds, the base unit of time, exact to the nanosecond
+		return formatExactFloat(v.Seconds()), nil
+	case *big.Int:
+		if v == nil {
+			return "", errors.New("Perfdata value is nil")
+		}
+
+		return v.String(), nil
+	case *big.Float:
+		if v == nil {
+			return "", errors.New("Perfdata value is nil")
+		}
+
+		if v.IsInf() {
+			return "", ErrPerfdataInf
+		}
+
+		// Decimal types keep their precision
+		return v.Text('f', -1), nil
+	case UnknownValue:
+		return PerfdataUnknownValue, nil
+	case json.Number:
+		return formatNumericString(v.String())
+	}
+
+	// Custom types based on numeric types, e.g. type Celsius float64
+	rv := reflect.ValueOf(value)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return formatNumeric(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return formatNumeric(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return formatNumeric(rv.Float())
+	}
+
+	if v, ok := value.(fmt.Stringer); ok {
+		return formatNumericString(v.String())
+	}
+
+	return "", fmt.Errorf("unsupported type for perfdata: %T", value)
+}
+
+// decimalRe matches decimal numbers without an exponent, which are valid perfdata values as they are
+var decimalRe = regexp.MustCompile(`^[-+]?(?:\d+(?:\.\d*)?|\.\d+)$`)
+
+// formatNumericString returns the string representation of a number given as string.
+// Decimal numbers are kept as they are to avoid losing precision, numbers with an exponent
+// are expanded, e.g. 1.23e-1 to 0.123.
+func formatNumericString(s string) (string, error) {
+	s = strings.TrimSpace(s)
+
+	if decimalRe.MatchString(s) {
+		return strings.TrimSuffix(strings.TrimPrefix(s, "+"), "."), nil
+	}
+
+	if strings.EqualFold(s, "nan") {
+		return "", ErrPerfdataNaN
 	}
+
+	f, _, err := big.ParseFloat(s, 10, 256, big.ToNearestEven)
+	if err != nil {
+		return "", fmt.Errorf("invalid numeric perfdata value: %q", s)
+	}
+
+	return formatNumeric(f)
 }
 
 // Perfdata represents all properties of performance data for Icinga
@@ -84,13 +217,19 @@ func formatNumeric(value any) (string, error) {
 // - https://icinga.com/docs/icinga-2/latest/doc/05-service-monitoring/#unit-of-measurement-uom
 type Perfdata struct {
 	Label string
+	// Check is the name of the check in the Icinga multi-check notation,
+	// the label is rendered as check::label if set
+	Check string
 	Value any
-	// Uom is the unit-of-measurement, see links above for details.
+	// Uom is the unit-of-measurement, see links above for details and UomSeconds and following.
 	Uom  string
 	Warn *Threshold
 	Crit *Threshold
 	Min  any
 	Max  any
+
+	// exact formats floats with full precision instead of three decimals, set when converting the unit
+	exact bool
 }
 
 // String returns the proper format for the plugin output
@@ -103,30 +242,52 @@ func (p Perfdata) String() string {
 // ValidatedString returns the proper format for the plugin output
 // Returns an error in some known cases where the value of a data type does not
 // represent a valid measurement, see the explanation for "formatNumeric" for
-// perfdata values.
+// perfdata values. Unknown units of measurement are rendered, but reported by Validate.
+// NaN and infinite values are handled according to InvalidValues.
 func (p Perfdata) ValidatedString() (string, error) {
 	var sb strings.Builder
 
+	if NormalizeUnits {
+		normalized, err := p.Normalize()
+		if err != nil {
+			return "", err
+		}
+
+		p = normalized
+	}
+
+	label := replacer.Replace(p.fullLabel())
+
 	// Add quotes if string contains any whitespace
-	if strings.ContainsAny(p.Label, "\t\n\f\r ") {
-		sb.WriteString(`'` + replacer.Replace(p.Label) + `'` + "=")
+	if strings.ContainsAny(label, "\t\n\f\r ") {
+		sb.WriteString(`'` + label + `'` + "=")
 	} else {
-		sb.WriteString(replacer.Replace(p.Label) + "=")
+		sb.WriteString(label + "=")
 	}
 
-	pfVal, err := formatNumeric(p.Value)
+	pfVal, err := p.formatNumeric(p.Value)
 	if err != nil {
-		return "", err
+		if InvalidValues != InvalidValueUnknown || !IsInvalidValue(err) {
+			return "", fmt.Errorf("invalid perfdata value for %s: %w", p.Label, err)
+		}
+
+		pfVal = PerfdataUnknownValue
 	}
 
 	sb.WriteString(pfVal)
-	sb.WriteString(p.Uom)
+
+	// The unit of measurement would be ambiguous for an unknown value
+	if pfVal != PerfdataUnknownValue {
+		sb.WriteString(p.Uom)
+	}
 
 	// Thresholds
 	for _, value := range []*Threshold{p.Warn, p.Crit} {
 		sb.WriteString(";")
 
-		if value != nil {
+		if value != nil && p.exact {
+			sb.WriteString(value.format(formatExactFloat))
+		} else if value != nil {
 			sb.WriteString(value.String())
 		}
 	}
@@ -136,7 +297,7 @@ func (p Perfdata) ValidatedString() (string, error) {
 		sb.WriteString(";")
 
 		if value != nil {
-			pfVal, err := formatNumeric(value)
+			pfVal, err := p.formatNumeric(value)
 			// Attention: we ignore limits if they are faulty
 			if err == nil {
 				sb.WriteString(pfVal)
@@ -146,3 +307,198 @@ func (p Perfdata) ValidatedString() (string, error) {
 
 	return strings.TrimRight(sb.String(), ";"), nil
 }
+
+// fullLabel returns the label in the Icinga multi-check notation, if the check is set
+func (p Perfdata) fullLabel() string {
+	if p.Check != "" {
+		return p.Check + PerfdataCheckSeparator + p.Label
+	}
+
+	return p.Label
+}
+
+// formatNumeric returns the string representation of a value or limit, see formatNumeric.
+// Floats are formatted with full precision for converted units, e.g. 500us as 0.0005s.
+func (p Perfdata) formatNumeric(value any) (string, error) {
+	if v, ok := value.(float64); ok && p.exact {
+		if err := checkFloat(v); err != nil {
+			return "", err
+		}
+
+		return formatExactFloat(v), nil
+	}
+
+	return formatNumeric(value)
+}
+
+// perfdataValueRe matches a value with an optional unit-of-measurement, e.g. "42.5ms"
+var perfdataValueRe = regexp.MustCompile(`^([-+]?(?:\d+(?:\.\d*)?|\.\d+)(?:[eE][-+]?\d+)?)([^\d;]*)$`)
+
+// ParsePerfdata parses the perfdata part of a plugin output, e.g. "time=0.5s;1;2;0 'disk::usage'=42%".
+//
+// Values, limits and thresholds are parsed as float64 and keep their precision when formatted again. Labels in the Icinga multi-check notation
+// are split at the first PerfdataCheckSeparator into Check and Label.
+func ParsePerfdata(s string) (PerfdataList, error) {
+	var list PerfdataList
+
+	tokens, err := splitPerfdata(s)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, token := range tokens {
+		p, err := parsePerfdataPoint(token)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, p)
+	}
+
+	return list, nil
+}
+
+// perfdataToken is a single perfdata point, split into label and the remaining fields
+type perfdataToken struct {
+	label  string
+	fields string
+}
+
+// splitPerfdata splits the perfdata into points, labels may be quoted with single quotes.
+// Within a quoted label, two single quotes represent a literal one.
+func splitPerfdata(s string) ([]perfdataToken, error) {
+	var tokens []perfdataToken
+
+	for i := 0; i < len(s); {
+		if s[i] == ' ' || s[i] == '\t' || s[i] == '\n' || s[i] == '\r' {
+			i++
+			continue
+		}
+
+		var label strings.Builder
+
+		if s[i] == '\'' {
+			closed := false
+
+			for i++; i < len(s); i++ {
+				if s[i] == '\'' {
+					if i+1 < len(s) && s[i+1] == '\'' {
+						label.WriteByte('\'')
+						i++
+
+						continue
+					}
+
+					closed = true
+					i++
+
+					break
+				}
+
+				label.WriteByte(s[i])
+			}
+
+			if !closed {
+				return nil, fmt.Errorf("unterminated quote in perfdata label: %s", label.String())
+			}
+		} else {
+			for ; i < len(s) && s[i] != '='; i++ {
+				if s[i] == ' ' {
+					return nil, fmt.Errorf("invalid perfdata, missing value for label: %s", label.String())
+				}
+
+				label.WriteByte(s[i])
+			}
+		}
+
+		if i >= len(s) || s[i] != '=' {
+			return nil, fmt.Errorf("invalid perfdata, missing value for label: %s", label.String())
+		}
+
+		start := i + 1
+
+		end := strings.IndexAny(s[start:], " \t\n\r")
+		if end < 0 {
+			end = len(s) - start
+		}
+
+		i = start + end
+
+		tokens = append(tokens, perfdataToken{label: label.String(), fields: s[start:i]})
+	}
+
+	return tokens, nil
+}
+
+// parsePerfdataPoint parses the fields of a single perfdata point: value[UOM];[warn];[crit];[min];[max]
+func parsePerfdataPoint(token perfdataToken) (*Perfdata, error) {
+	// Parsed values are formatted exactly, so they are emitted without loss of precision
+	p := &Perfdata{Label: token.label, exact: true}
+
+	if check, label, found := strings.Cut(token.label, PerfdataCheckSeparator); found {
+		p.Check = check
+		p.Label = label
+	}
+
+	if p.Label == "" {
+		return nil, fmt.Errorf("invalid perfdata, empty label: %s", token.label)
+	}
+
+	fields := strings.Split(token.fields, ";")
+	if len(fields) > 5 {
+		return nil, fmt.Errorf("invalid perfdata for %s, too many fields: %s", token.label, token.fields)
+	}
+
+	if fields[0] == PerfdataUnknownValue {
+		p.Value = UnknownValue{}
+	} else if err := parsePerfdataValue(p, fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid perfdata value for %s: %w", token.label, err)
+	}
+
+	thresholds := []**Threshold{&p.Warn, &p.Crit}
+	limits := []*any{&p.Min, &p.Max}
+
+	for i, field := range fields[1:] {
+		if field == "" {
+			continue
+		}
+
+		if i < len(thresholds) {
+			threshold, err := ParseThreshold(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid perfdata threshold for %s: %w", token.label, err)
+			}
+
+			*thresholds[i] = threshold
+
+			continue
+		}
+
+		limit, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid perfdata limit for %s: %w", token.label, err)
+		}
+
+		*limits[i-len(thresholds)] = limit
+	}
+
+	return p, nil
+}
+
+// parsePerfdataValue parses the value with an optional unit-of-measurement, e.g. "42.5ms"
+func parsePerfdataValue(p *Perfdata, field string) error {
+	match := perfdataValueRe.FindStringSubmatch(field)
+	if match == nil {
+		return fmt.Errorf("not a number: %s", field)
+	}
+
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return err
+	}
+
+	p.Value = value
+	p.Uom = match[2]
+
+	return nil
+}
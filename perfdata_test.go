@@ -1,9 +1,13 @@
 package check
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"testing"
+	"time"
 )
 
 func ExamplePerfdataList() {
@@ -152,7 +156,22 @@ type pfFormatTest struct {
 	InputValue any
 }
 
+// celsius is a custom numeric type, its fmt.Stringer is not used for perfdata
+type celsius float64
+
+func (c celsius) String() string {
+	return fmt.Sprintf("%.1f°C", float64(c))
+}
+
+type stringerNumber struct{}
+
+func (stringerNumber) String() string {
+	return "42"
+}
+
 func TestFormatNumeric(t *testing.T) {
+	bigInt, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
 	testdata := []pfFormatTest{
 		{
 			Result:     "10",
@@ -178,6 +197,54 @@ func TestFormatNumeric(t *testing.T) {
 			Result:     "1234567890.988",
 			InputValue: 1234567890.9877,
 		},
+		{
+			Result:     "123456789012345678901234567890",
+			InputValue: bigInt,
+		},
+		{
+			Result:     "12.5",
+			InputValue: big.NewFloat(12.5),
+		},
+		{
+			Result:     "18446744073709551616",
+			InputValue: json.Number("18446744073709551616"),
+		},
+		{
+			Result:     "0.123",
+			InputValue: json.Number("1.23e-1"),
+		},
+		{
+			Result:     "1.5",
+			InputValue: 1500 * time.Millisecond,
+		},
+		{
+			Result:     "0.0004",
+			InputValue: 400 * time.Microsecond,
+		},
+		{
+			Result:     "0.0015",
+			InputValue: 1500 * time.Microsecond,
+		},
+		{
+			Result:     "21.5",
+			InputValue: celsius(21.5),
+		},
+		{
+			Result:     "0.0001234",
+			InputValue: big.NewFloat(0.0001234),
+		},
+		{
+			Result:     "0.000123456789012345678",
+			InputValue: json.Number("0.000123456789012345678"),
+		},
+		{
+			Result:     "-12.3456789",
+			InputValue: json.Number("-12.3456789"),
+		},
+		{
+			Result:     "42",
+			InputValue: stringerNumber{},
+		},
 	}
 
 	for _, val := range testdata {
@@ -191,3 +258,176 @@ func TestFormatNumeric(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatNumeric_WithErr(t *testing.T) {
+	testcases := map[string]any{
+		"nil":         nil,
+		"string":      "42",
+		"struct":      struct{}{},
+		"nil-big-int": (*big.Int)(nil),
+		"big-inf":     new(big.Float).SetInf(false),
+		"json-text":   json.Number("abc"),
+		"json-inf":    json.Number("Inf"),
+		"json-nan":    json.Number("NaN"),
+		"stringer":    time.UTC,
+	}
+
+	for name, value := range testcases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := formatNumeric(value); err == nil {
+				t.Fatalf("expected error, got none")
+			}
+		})
+	}
+}
+
+func TestPerfdata_Check(t *testing.T) {
+	p := Perfdata{Check: "disk", Label: "usage", Value: 42, Uom: "%"}
+
+	if p.String() != "disk::usage=42%" {
+		t.Fatalf("expected %v, got %v", "disk::usage=42%", p.String())
+	}
+
+	p = Perfdata{Check: "disk 1", Label: "usage", Value: 42}
+
+	if p.String() != "'disk 1::usage'=42" {
+		t.Fatalf("expected %v, got %v", "'disk 1::usage'=42", p.String())
+	}
+}
+
+func TestParsePerfdata(t *testing.T) {
+	input := "time=0.5s;1;~:2;0;10 'disk::my usage'=42% 'it''s'=1 empty=3;;;;5 count=-1e3c"
+
+	list, err := ParsePerfdata(input)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(list) != 5 {
+		t.Fatalf("expected %d points, got %d", 5, len(list))
+	}
+
+	duration := list[0]
+
+	if duration.Label != "time" || duration.Value != 0.5 || duration.Uom != "s" || duration.Min != 0.0 || duration.Max != 10.0 {
+		t.Fatalf("unexpected perfdata: %#v", duration)
+	}
+
+	if duration.Warn.String() != "1" || duration.Crit.String() != "~:2" {
+		t.Fatalf("expected thresholds 1 and ~:2, got %v and %v", duration.Warn, duration.Crit)
+	}
+
+	if list[1].Check != "disk" || list[1].Label != "my usage" || list[1].Uom != "%" {
+		t.Fatalf("unexpected perfdata: %#v", list[1])
+	}
+
+	if list[2].Label != "it's" {
+		t.Fatalf("expected %v, got %v", "it's", list[2].Label)
+	}
+
+	if list[3].Warn != nil || list[3].Min != nil || list[3].Max != 5.0 {
+		t.Fatalf("unexpected perfdata: %#v", list[3])
+	}
+
+	if list[4].Value != -1000.0 || list[4].Uom != "c" {
+		t.Fatalf("unexpected perfdata: %#v", list[4])
+	}
+
+	// Round trip
+	list, _ = ParsePerfdata("time=0.5s;1;2;0;10 'disk::my usage'=42%")
+
+	if list.String() != "time=0.5s;1;2;0;10 'disk::my usage'=42%" {
+		t.Fatalf("expected %v, got %v", "time=0.5s;1;2;0;10 'disk::my usage'=42%", list.String())
+	}
+}
+
+func TestParsePerfdata_RoundTrip(t *testing.T) {
+	testcases := []string{
+		"time=0.0004s;0.0005;0.00075:0.001;0;0.0015",
+		"load=1.23456789;~:0.0001;@0.5:0.75",
+		"bytes=123456789012B;;;0;999999999999",
+		"small=-0.000012345",
+		"unknown=U;1;2",
+	}
+
+	for _, input := range testcases {
+		list, err := ParsePerfdata(input)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if list.String() != input {
+			t.Fatalf("expected %v, got %v", input, list.String())
+		}
+	}
+}
+
+func TestParsePerfdata_WithErr(t *testing.T) {
+	testcases := []string{
+		"time",
+		"time =1",
+		"'time=1",
+		"time=abc",
+		"time=1;foo",
+		"time=1;1;2;a",
+		"time=1;1;2;3;4;5",
+		"disk::=1",
+	}
+
+	for _, input := range testcases {
+		if _, err := ParsePerfdata(input); err == nil {
+			t.Fatalf("expected error for %q", input)
+		}
+	}
+}
+
+func TestPerfdata_InvalidValues(t *testing.T) {
+	nan := Perfdata{Label: "time", Value: math.NaN(), Uom: "s", Warn: &Threshold{Upper: 1}}
+	inf := Perfdata{Label: "time", Value: float32(math.Inf(-1)), Uom: "s"}
+
+	if _, err := nan.ValidatedString(); !errors.Is(err, ErrPerfdataNaN) {
+		t.Fatalf("expected %v, got %v", ErrPerfdataNaN, err)
+	}
+
+	if _, err := inf.ValidatedString(); !errors.Is(err, ErrPerfdataInf) {
+		t.Fatalf("expected %v, got %v", ErrPerfdataInf, err)
+	}
+
+	defer func() {
+		InvalidValues = InvalidValueDrop
+	}()
+
+	InvalidValues = InvalidValueUnknown
+
+	if nan.String() != "time=U;1" {
+		t.Fatalf("expected %v, got %v", "time=U;1", nan.String())
+	}
+
+	if inf.String() != "time=U" {
+		t.Fatalf("expected %v, got %v", "time=U", inf.String())
+	}
+
+	// Unsupported types are still dropped
+	p := Perfdata{Label: "time", Value: "abc"}
+
+	if _, err := p.ValidatedString(); err == nil || IsInvalidValue(err) {
+		t.Fatalf("expected unsupported type error, got %v", err)
+	}
+}
+
+func TestPerfdata_UnknownValue(t *testing.T) {
+	p := Perfdata{Label: "time", Value: UnknownValue{}, Uom: "s", Crit: &Threshold{Upper: 2}, Min: 0}
+
+	if p.String() != "time=U;;2;0" {
+		t.Fatalf("expected %v, got %v", "time=U;;2;0", p.String())
+	}
+
+	list, err := ParsePerfdata("time=U;;2;0 load=1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if list[0].Value != (UnknownValue{}) || list.String() != "time=U;;2;0 load=1" {
+		t.Fatalf("expected %v, got %v", "time=U;;2;0 load=1", list.String())
+	}
+}
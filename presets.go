@@ -0,0 +1,146 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// HostOptions contains the values of the flags registered by AddHostFlags
+type HostOptions struct {
+	Hostname string
+	Port     int
+}
+
+// Address returns the host and port combined, e.g. "localhost:443" or "[::1]:443"
+func (o *HostOptions) Address() string {
+	return net.JoinHostPort(o.Hostname, strconv.Itoa(o.Port))
+}
+
+// AddHostFlags registers the --hostname (-H) and --port (-p) flags.
+// The values are validated when the arguments are parsed.
+func (c *Config) AddHostFlags(defaultHostname string, defaultPort int) *HostOptions {
+	o := &HostOptions{}
+
+	c.FlagSet.StringVarP(&o.Hostname, "hostname", "H", defaultHostname, "Hostname or address to connect to")
+	c.FlagSet.IntVarP(&o.Port, "port", "p", defaultPort, "Port to connect to")
+
+	c.validators = append(c.validators, func() error {
+		if o.Hostname == "" {
+			return errors.New("hostname must not be empty")
+		}
+
+		if o.Port < 1 || o.Port > 65535 {
+			return fmt.Errorf("invalid port: %d", o.Port)
+		}
+
+		return nil
+	})
+
+	return o
+}
+
+// AuthOptions contains the values of the flags registered by AddAuthFlags
+type AuthOptions struct {
+	Username string
+	Password string
+}
+
+// AddAuthFlags registers the --username (-u) and --password flags.
+// The password is redacted from the output of the Logger, regardless of whether it is created
+// before or after the arguments are parsed.
+//
+// Note that, arguments are visible in the process list. See LoadFromEnv to pass the password
+// via the environment instead.
+func (c *Config) AddAuthFlags() *AuthOptions {
+	o := &AuthOptions{}
+
+	c.FlagSet.StringVarP(&o.Username, "username", "u", "", "Username for the authentication")
+	c.FlagSet.StringVar(&o.Password, "password", "", "Password for the authentication")
+
+	c.secretFlags = append(c.secretFlags, &o.Password)
+
+	c.validators = append(c.validators, func() error {
+		if o.Password != "" && o.Username == "" {
+			return errors.New("password given without username")
+		}
+
+		return nil
+	})
+
+	return o
+}
+
+// ThresholdOptions contains the values of the flags registered by AddThresholdFlags.
+// A threshold is nil, if it was not given and has no default.
+type ThresholdOptions struct {
+	Warning  *Threshold
+	Critical *Threshold
+}
+
+// AddThresholdFlags registers the --warning (-w) and --critical (-c) flags.
+// The thresholds are parsed with ParseThreshold, an empty default means no threshold.
+func (c *Config) AddThresholdFlags(defaultWarning, defaultCritical string) *ThresholdOptions {
+	o := &ThresholdOptions{}
+
+	warning := newThresholdValue(&o.Warning)
+	critical := newThresholdValue(&o.Critical)
+
+	// Invalid defaults are reported when parsing the arguments
+	warnErr := warning.Set(defaultWarning)
+	critErr := critical.Set(defaultCritical)
+
+	c.FlagSet.VarP(warning, "warning", "w", "Warning threshold")
+	c.FlagSet.VarP(critical, "critical", "c", "Critical threshold")
+
+	c.validators = append(c.validators, func() error {
+		if warnErr != nil && !c.FlagSet.Changed("warning") {
+			return fmt.Errorf("invalid default for --warning: %w", warnErr)
+		}
+
+		if critErr != nil && !c.FlagSet.Changed("critical") {
+			return fmt.Errorf("invalid default for --critical: %w", critErr)
+		}
+
+		return nil
+	})
+
+	return o
+}
+
+// thresholdValue implements the pflag.Value interface for a Threshold
+type thresholdValue struct {
+	spec      string
+	threshold **Threshold
+}
+
+func newThresholdValue(threshold **Threshold) *thresholdValue {
+	return &thresholdValue{threshold: threshold}
+}
+
+func (v *thresholdValue) Set(spec string) error {
+	v.spec = spec
+
+	if spec == "" {
+		*v.threshold = nil
+		return nil
+	}
+
+	t, err := ParseThreshold(spec)
+	if err != nil {
+		return err
+	}
+
+	*v.threshold = t
+
+	return nil
+}
+
+func (v *thresholdValue) String() string {
+	return v.spec
+}
+
+func (v *thresholdValue) Type() string {
+	return "threshold"
+}
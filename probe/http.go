@@ -0,0 +1,402 @@
+// Package probe provides building blocks to probe network services and to report the results
+package probe
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NETWAYS/go-check"
+	"github.com/NETWAYS/go-check/result"
+)
+
+// DefaultMaxBodySize is the maximum number of bytes read from a response body
+const DefaultMaxBodySize = 1024 * 1024
+
+// HTTPOptions describes an HTTP request and the expectations on its response
+type HTTPOptions struct {
+	// Method of the request, defaults to GET
+	Method string
+	URL    string
+	// Name is used as prefix for the perfdata labels, e.g. "api_time". Defaults to the host of the URL
+	// with all special characters replaced, e.g. "localhost_8080_time".
+	Name   string
+	Header http.Header
+	Body   string
+	// Timeout for the whole request, e.g. derived from check.Config.Timeout
+	Timeout time.Duration
+	// Client used for the request. When nil, a client with TLSConfig is created.
+	Client    *http.Client
+	TLSConfig *tls.Config
+	// FollowRedirects only applies to the created client
+	FollowRedirects bool
+	// MaxBodySize limits the bytes read from the body, defaults to DefaultMaxBodySize
+	MaxBodySize int64
+
+	// ExpectedStatus lists the accepted status codes, by default all codes below 400 are accepted
+	ExpectedStatus []int
+	// BodyRegex must match the response body
+	BodyRegex *regexp.Regexp
+	// JSON maps paths in a JSON body to their expected values, e.g. "status.items.0.name": "foo"
+	JSON map[string]string
+	// Thresholds for the total duration of the request in seconds
+	Warning  *check.Threshold
+	Critical *check.Threshold
+}
+
+// HTTPTimings contains the durations of the phases of an HTTP request.
+// Phases that did not happen, e.g. DNS for an IP address, are zero.
+type HTTPTimings struct {
+	DNS       time.Duration
+	Connect   time.Duration
+	TLS       time.Duration
+	FirstByte time.Duration
+	Total     time.Duration
+}
+
+// httpTrace records the timings of a request via httptrace
+type httpTrace struct {
+	start time.Time
+
+	mu                            sync.Mutex
+	dnsStart, connStart, tlsStart time.Time
+	timings                       HTTPTimings
+}
+
+func (h *httpTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { h.record(func() { h.dnsStart = time.Now() }) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { h.record(func() { h.timings.DNS = time.Since(h.dnsStart) }) },
+		ConnectStart: func(string, string) {
+			h.record(func() {
+				if h.connStart.IsZero() {
+					h.connStart = time.Now()
+				}
+			})
+		},
+		ConnectDone: func(_, _ string, err error) {
+			h.record(func() {
+				if err == nil {
+					h.timings.Connect = time.Since(h.connStart)
+				}
+			})
+		},
+		TLSHandshakeStart: func() { h.record(func() { h.tlsStart = time.Now() }) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			h.record(func() { h.timings.TLS = time.Since(h.tlsStart) })
+		},
+		GotFirstResponseByte: func() { h.record(func() { h.timings.FirstByte = time.Since(h.start) }) },
+	}
+}
+
+// record runs f while holding the lock, since some callbacks may be called concurrently
+func (h *httpTrace) record(f func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f()
+}
+
+// HTTP performs the request described by the options and returns a PartialResult
+// with a subcheck for every expectation and the timings as perfdata.
+//
+// Failed requests and unmet expectations are critical, the thresholds
+// are evaluated against the total duration in seconds.
+func HTTP(ctx context.Context, opts HTTPOptions) *result.PartialResult {
+	pr := result.NewPartialResult()
+
+	method := opts.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	pr.SetOutput(fmt.Sprintf("HTTP %s %s", method, opts.URL))
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	trace := &httpTrace{}
+	ctx = httptrace.WithClientTrace(ctx, trace.clientTrace())
+
+	req, err := http.NewRequestWithContext(ctx, method, opts.URL, strings.NewReader(opts.Body))
+	if err != nil {
+		pr.SetState(check.Unknown)
+		pr.SetOutput(fmt.Sprintf("HTTP %s %s: could not create request: %s", method, opts.URL, err))
+
+		return pr
+	}
+
+	for key, values := range opts.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	trace.start = time.Now()
+
+	resp, err := httpClient(opts).Do(req)
+	if err != nil {
+		pr.SetState(check.Critical)
+		pr.SetOutput(fmt.Sprintf("HTTP %s %s failed: %s", method, opts.URL, err))
+		// Keep the graph of the duration without gaps, e.g. for timeouts
+		pr.AddPerfdata(opts.timePerfdata(time.Since(trace.start)))
+
+		return pr
+	}
+	defer resp.Body.Close()
+
+	maxBodySize := opts.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+
+	trace.mu.Lock()
+	trace.timings.Total = time.Since(trace.start)
+	timings := trace.timings
+	trace.mu.Unlock()
+
+	if err != nil {
+		pr.SetState(check.Critical)
+		pr.SetOutput(fmt.Sprintf("HTTP %s %s: could not read body: %s", method, opts.URL, err))
+		pr.AddPerfdata(opts.timePerfdata(timings.Total))
+
+		return pr
+	}
+
+	pr.SetOutput(fmt.Sprintf("HTTP %s %s returned %s in %s", method, opts.URL, resp.Status, timings.Total))
+
+	pr.AddSubcheck(checkStatusCode(resp.StatusCode, opts.ExpectedStatus))
+
+	if opts.BodyRegex != nil {
+		pr.AddSubcheck(checkBodyRegex(body, opts.BodyRegex))
+	}
+
+	if len(opts.JSON) > 0 {
+		pr.AddSubcheck(checkJSON(body, opts.JSON))
+	}
+
+	pr.AddSubcheck(checkDuration(timings.Total, opts))
+
+	for _, p := range []struct {
+		label    string
+		duration time.Duration
+	}{
+		{"time_dns", timings.DNS},
+		{"time_connect", timings.Connect},
+		{"time_tls", timings.TLS},
+		{"time_firstbyte", timings.FirstByte},
+	} {
+		// Durations are rendered in seconds with full precision
+		pr.AddPerfdata(&check.Perfdata{Label: opts.label(p.label), Value: p.duration, Uom: "s", Min: 0})
+	}
+
+	pr.AddPerfdata(&check.Perfdata{Label: opts.label("size"), Value: len(body), Uom: "B", Min: 0})
+
+	return pr
+}
+
+// label returns the perfdata label prefixed with the name of the probe or the host of the URL,
+// so multiple probes in one Overall have distinct labels
+func (o HTTPOptions) label(label string) string {
+	if o.Name != "" {
+		return o.Name + "_" + label
+	}
+
+	u, err := url.Parse(o.URL)
+	if err != nil || u.Host == "" {
+		return label
+	}
+
+	return labelReplacer.Replace(u.Host) + "_" + label
+}
+
+// timePerfdata returns the perfdata for the total duration of the request
+func (o HTTPOptions) timePerfdata(duration time.Duration) *check.Perfdata {
+	return &check.Perfdata{Label: o.label("time"), Value: duration, Uom: "s", Warn: o.Warning, Crit: o.Critical, Min: 0}
+}
+
+// httpClient returns the client of the options or creates a new one.
+// A new client does not keep connections alive, since it is only used for a single request.
+func httpClient(opts HTTPOptions) *http.Client {
+	if opts.Client != nil {
+		return opts.Client
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			TLSClientConfig:   opts.TLSConfig,
+			DisableKeepAlives: true,
+		},
+	}
+
+	if !opts.FollowRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	return client
+}
+
+func checkStatusCode(code int, expected []int) *result.PartialResult {
+	pr := result.NewPartialResult()
+	pr.SetState(check.OK)
+	pr.SetOutput(fmt.Sprintf("Status code %d", code))
+
+	if len(expected) == 0 {
+		if code >= http.StatusBadRequest {
+			pr.SetState(check.Critical)
+		}
+
+		return pr
+	}
+
+	for _, e := range expected {
+		if code == e {
+			return pr
+		}
+	}
+
+	pr.SetState(check.Critical)
+	pr.SetOutput(fmt.Sprintf("Status code %d, expected %s", code, strings.Trim(fmt.Sprint(expected), "[]")))
+
+	return pr
+}
+
+func checkBodyRegex(body []byte, re *regexp.Regexp) *result.PartialResult {
+	pr := result.NewPartialResult()
+
+	if re.Match(body) {
+		pr.SetState(check.OK)
+		pr.SetOutput(fmt.Sprintf("Body matches %q", re))
+	} else {
+		pr.SetState(check.Critical)
+		pr.SetOutput(fmt.Sprintf("Body does not match %q", re))
+	}
+
+	return pr
+}
+
+func checkJSON(body []byte, expected map[string]string) *result.PartialResult {
+	pr := result.NewPartialResult()
+	pr.SetOutput("JSON body")
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var document any
+
+	if err := decoder.Decode(&document); err != nil {
+		pr.SetState(check.Critical)
+		pr.SetOutput(fmt.Sprintf("Body is not valid JSON: %s", err))
+
+		return pr
+	}
+
+	paths := make([]string, 0, len(expected))
+	for path := range expected {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		want := expected[path]
+		sc := result.NewPartialResult()
+
+		value, err := lookupJSON(document, path)
+
+		switch {
+		case err != nil:
+			sc.SetState(check.Critical)
+			sc.SetOutput(fmt.Sprintf("%s: %s", path, err))
+		case value != want:
+			sc.SetState(check.Critical)
+			sc.SetOutput(fmt.Sprintf("%s is %q, expected %q", path, value, want))
+		default:
+			sc.SetState(check.OK)
+			sc.SetOutput(fmt.Sprintf("%s is %q", path, value))
+		}
+
+		pr.AddSubcheck(sc)
+	}
+
+	return pr
+}
+
+// lookupJSON returns the string representation of the value at a dot separated path.
+// Numeric path elements are used as index for arrays.
+func lookupJSON(document any, path string) (string, error) {
+	current := document
+
+	for _, key := range strings.Split(path, ".") {
+		switch v := current.(type) {
+		case map[string]any:
+			next, ok := v[key]
+			if !ok {
+				return "", fmt.Errorf("key %q not found", key)
+			}
+
+			current = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", fmt.Errorf("invalid index %q", key)
+			}
+
+			current = v[i]
+		default:
+			return "", errors.New("path not found")
+		}
+	}
+
+	switch v := current.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return v, nil
+	case json.Number, bool:
+		return fmt.Sprint(v), nil
+	default:
+		data, err := json.Marshal(v)
+		return string(data), err
+	}
+}
+
+func checkDuration(duration time.Duration, opts HTTPOptions) *result.PartialResult {
+	pr := result.NewPartialResult()
+	pr.SetOutput(fmt.Sprintf("Response time %s", duration))
+
+	switch {
+	case opts.Critical != nil && opts.Critical.DoesViolate(duration.Seconds()):
+		pr.SetState(check.Critical)
+	case opts.Warning != nil && opts.Warning.DoesViolate(duration.Seconds()):
+		pr.SetState(check.Warning)
+	default:
+		pr.SetState(check.OK)
+	}
+
+	pr.AddPerfdata(opts.timePerfdata(duration))
+
+	return pr
+}
@@ -0,0 +1,189 @@
+package probe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NETWAYS/go-check"
+	"github.com/NETWAYS/go-check/result"
+)
+
+func newTestServer() *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/status", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status": "green", "nodes": [{"name": "node1", "up": true, "shards": 5}]}`))
+	})
+
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte("slow"))
+	})
+
+	mux.HandleFunc("/error", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func getOutput(pr *result.PartialResult) string {
+	var overall result.Overall
+
+	overall.AddSubcheck(pr)
+
+	return overall.GetOutput()
+}
+
+func TestHTTP(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	pr := HTTP(context.Background(), HTTPOptions{
+		URL:            server.URL + "/status",
+		Timeout:        5 * time.Second,
+		ExpectedStatus: []int{http.StatusOK},
+		BodyRegex:      regexp.MustCompile(`green`),
+		JSON: map[string]string{
+			"status":         "green",
+			"nodes.0.name":   "node1",
+			"nodes.0.up":     "true",
+			"nodes.0.shards": "5",
+		},
+		Critical: &check.Threshold{Upper: 5},
+	})
+
+	output := getOutput(pr)
+
+	if pr.GetStatus() != check.OK {
+		t.Fatalf("expected %v, got %v: %s", check.OK, pr.GetStatus(), output)
+	}
+
+	for _, expected := range []string{"returned 200 OK", "time=", ";;5;0", "time_connect=", "time_firstbyte=", "size=74B;;;0"} {
+		if !strings.Contains(output, expected) {
+			t.Fatalf("expected %q in output, got %q", expected, output)
+		}
+	}
+}
+
+func TestHTTP_Labels(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	var overall result.Overall
+
+	overall.AddSubcheck(HTTP(context.Background(), HTTPOptions{URL: server.URL + "/status"}))
+	overall.AddSubcheck(HTTP(context.Background(), HTTPOptions{URL: server.URL + "/status", Name: "api"}))
+
+	if labels := overall.DuplicatePerfdataLabels(); len(labels) != 0 {
+		t.Fatalf("expected no duplicates, got %v", labels)
+	}
+
+	output := overall.GetOutput()
+	prefix := strings.NewReplacer(":", "_").Replace(strings.TrimPrefix(server.URL, "http://"))
+
+	for _, expected := range []string{"|" + prefix + "_time_dns=", " api_time_dns="} {
+		if !strings.Contains(output, expected) {
+			t.Fatalf("expected %q in output, got %q", expected, output)
+		}
+	}
+
+	// The timings are not rounded to milliseconds
+	if strings.Contains(output, "time_firstbyte=0s") {
+		t.Fatalf("expected time to first byte, got %q", output)
+	}
+}
+
+func TestHTTP_WithFailures(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	testcases := map[string]struct {
+		opts     HTTPOptions
+		expected check.Status
+		output   string
+	}{
+		"status-code": {
+			opts:     HTTPOptions{URL: server.URL + "/error"},
+			expected: check.Critical,
+			output:   "Status code 503",
+		},
+		"unexpected-status-code": {
+			opts:     HTTPOptions{URL: server.URL + "/status", ExpectedStatus: []int{http.StatusCreated}},
+			expected: check.Critical,
+			output:   "Status code 200, expected 201",
+		},
+		"body-regex": {
+			opts:     HTTPOptions{URL: server.URL + "/status", BodyRegex: regexp.MustCompile(`red`)},
+			expected: check.Critical,
+			output:   "Body does not match",
+		},
+		"json-value": {
+			opts:     HTTPOptions{URL: server.URL + "/status", JSON: map[string]string{"status": "red"}},
+			expected: check.Critical,
+			output:   `status is "green", expected "red"`,
+		},
+		"json-path": {
+			opts:     HTTPOptions{URL: server.URL + "/status", JSON: map[string]string{"nodes.1.name": "node2"}},
+			expected: check.Critical,
+			output:   `nodes.1.name: invalid index "1"`,
+		},
+		"latency": {
+			opts:     HTTPOptions{URL: server.URL + "/slow", Warning: &check.Threshold{Upper: 0.01}},
+			expected: check.Warning,
+			output:   "Response time",
+		},
+		"timeout": {
+			opts:     HTTPOptions{URL: server.URL + "/slow", Timeout: 10 * time.Millisecond},
+			expected: check.Critical,
+			output:   "context deadline exceeded",
+		},
+		"invalid-url": {
+			opts:     HTTPOptions{URL: "://"},
+			expected: check.Unknown,
+			output:   "could not create request",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			pr := HTTP(context.Background(), tc.opts)
+			output := getOutput(pr)
+
+			if pr.GetStatus() != tc.expected {
+				t.Fatalf("expected %v, got %v: %s", tc.expected, pr.GetStatus(), output)
+			}
+
+			if !strings.Contains(output, tc.output) {
+				t.Fatalf("expected %q in output, got %q", tc.output, output)
+			}
+
+			if tc.expected == check.Critical && !strings.Contains(output, "_time=") {
+				t.Fatalf("expected time perfdata, got %q", output)
+			}
+		})
+	}
+}
+
+func TestHTTP_WithTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	pr := HTTP(context.Background(), HTTPOptions{URL: server.URL, Client: server.Client()})
+
+	if pr.GetStatus() != check.OK {
+		t.Fatalf("expected %v, got %v: %s", check.OK, pr.GetStatus(), getOutput(pr))
+	}
+
+	if strings.Contains(getOutput(pr), "time_tls=0s") {
+		t.Fatalf("expected a TLS handshake duration, got %q", getOutput(pr))
+	}
+}
@@ -0,0 +1,276 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/NETWAYS/go-check"
+	"github.com/NETWAYS/go-check/result"
+)
+
+// DefaultMaxResponseSize is the maximum number of bytes read from a port response
+const DefaultMaxResponseSize = 4096
+
+// Failure is the kind of failure of a port probe
+type Failure int
+
+const (
+	// FailureRefused means the connection was refused
+	FailureRefused Failure = iota
+	// FailureTimeout means the connection or the response timed out
+	FailureTimeout
+	// FailureDNS means the hostname could not be resolved
+	FailureDNS
+	// FailureMismatch means the response did not match the expected pattern
+	FailureMismatch
+	// FailureError is any other error, e.g. a connection reset
+	FailureError
+)
+
+// DefaultFailureStates are used for failures without a state in PortOptions.FailureStates
+var DefaultFailureStates = map[Failure]check.Status{
+	FailureRefused:  check.Critical,
+	FailureTimeout:  check.Critical,
+	FailureDNS:      check.Unknown,
+	FailureMismatch: check.Warning,
+	FailureError:    check.Critical,
+}
+
+// PortOptions describes a connection to a network port and the expected response
+type PortOptions struct {
+	// Network is "tcp" (default), "udp" or any other network supported by net.Dial.
+	// UDP requires Expect, since sending to a closed port succeeds without a response.
+	Network string
+	// Address to connect to, e.g. "localhost:22"
+	Address string
+	// Name is used in the output and as prefix for the perfdata labels, e.g. "ssh_time".
+	// Defaults to the address with all special characters replaced, e.g. "localhost_22_time",
+	// and the network for other networks than TCP, e.g. "udp_localhost_53_time".
+	Name string
+	// Timeout for connecting and receiving the response
+	Timeout time.Duration
+	// Send is written after the connection has been established
+	Send string
+	// Expect must match the response. Without Expect no response is read.
+	Expect *regexp.Regexp
+	// MaxResponseSize limits the bytes read, defaults to DefaultMaxResponseSize
+	MaxResponseSize int
+	// FailureStates maps failures to the resulting state, see DefaultFailureStates
+	FailureStates map[Failure]check.Status
+	// Thresholds for the total duration of the probe in seconds
+	Warning  *check.Threshold
+	Critical *check.Threshold
+}
+
+// failureState returns the configured state for a failure
+func (o PortOptions) failureState(f Failure) check.Status {
+	if state, ok := o.FailureStates[f]; ok {
+		return state
+	}
+
+	return DefaultFailureStates[f]
+}
+
+// network returns the network of the probe, "tcp" by default
+func (o PortOptions) network() string {
+	if o.Network == "" {
+		return "tcp"
+	}
+
+	return o.Network
+}
+
+// label returns the perfdata label prefixed with the name of the probe or the address,
+// so multiple probes in one Overall have distinct labels
+func (o PortOptions) label(label string) string {
+	if o.Name != "" {
+		return o.Name + "_" + label
+	}
+
+	prefix := o.Address
+	if o.network() != "tcp" {
+		prefix = o.network() + "_" + prefix
+	}
+
+	return labelReplacer.Replace(prefix) + "_" + label
+}
+
+// timePerfdata returns the perfdata for the total duration of the probe
+func (o PortOptions) timePerfdata(duration time.Duration) *check.Perfdata {
+	return &check.Perfdata{Label: o.label("time"), Value: duration, Uom: "s", Warn: o.Warning, Crit: o.Critical, Min: 0}
+}
+
+// labelReplacer replaces the special characters of addresses in perfdata labels
+var labelReplacer = strings.NewReplacer(":", "_", "[", "", "]", "", "/", "_", " ", "_")
+
+// Port connects to a network port, optionally sends a payload and matches the response.
+// The returned PartialResult contains the connect and response time as perfdata,
+// the total time is also added for failures.
+//
+// UDP is connectionless, a closed port is only detected by the ICMP error when reading the response,
+// which is reported as FailureRefused. Therefore, PortOptions.Expect is required for UDP.
+//
+// Failures are mapped to states by PortOptions.FailureStates, the thresholds
+// are evaluated against the total duration in seconds.
+// Port can be called concurrently to probe multiple ports into one Overall.
+func Port(ctx context.Context, opts PortOptions) *result.PartialResult {
+	pr := result.NewPartialResult()
+
+	network := opts.network()
+
+	name := opts.Name
+	if name == "" {
+		name = strings.ToUpper(network) + " " + opts.Address
+	}
+
+	if strings.HasPrefix(network, "udp") && opts.Expect == nil {
+		pr.SetState(check.Unknown)
+		pr.SetOutput(fmt.Sprintf("%s: an expected response is required for UDP", name))
+
+		return pr
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, network, opts.Address)
+	if err != nil {
+		pr.SetState(opts.failureState(classifyError(err)))
+		pr.SetOutput(fmt.Sprintf("%s: connection failed: %s", name, err))
+		pr.AddPerfdata(opts.timePerfdata(time.Since(start)))
+
+		return pr
+	}
+	defer conn.Close()
+
+	connectTime := time.Since(start)
+
+	pr.AddPerfdata(&check.Perfdata{Label: opts.label("time_connect"), Value: connectTime, Uom: "s", Min: 0})
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if opts.Send != "" {
+		if _, err := conn.Write([]byte(opts.Send)); err != nil {
+			pr.SetState(opts.failureState(classifyError(err)))
+			pr.SetOutput(fmt.Sprintf("%s: could not send payload: %s", name, err))
+			pr.AddPerfdata(opts.timePerfdata(time.Since(start)))
+
+			return pr
+		}
+	}
+
+	output := fmt.Sprintf("%s: connected in %s", name, connectTime)
+	state := check.OK
+
+	if opts.Expect != nil {
+		response, err := readResponse(conn, opts.Expect, opts.MaxResponseSize)
+		responseTime := time.Since(start) - connectTime
+
+		pr.AddPerfdata(&check.Perfdata{Label: opts.label("time_response"), Value: responseTime, Uom: "s", Min: 0})
+
+		switch {
+		case opts.Expect.Match(response):
+			output += fmt.Sprintf(", response matched in %s", responseTime)
+		case err != nil:
+			pr.SetState(opts.failureState(classifyError(err)))
+			pr.SetOutput(fmt.Sprintf("%s: no matching response: %s", name, err))
+			pr.AddPerfdata(opts.timePerfdata(time.Since(start)))
+
+			return pr
+		default:
+			state = opts.failureState(FailureMismatch)
+			output += fmt.Sprintf(", response %q does not match %q", truncate(response, 64), opts.Expect)
+		}
+	}
+
+	total := time.Since(start)
+
+	switch {
+	case opts.Critical != nil && opts.Critical.DoesViolate(total.Seconds()):
+		state = check.WorstState(state, check.Critical)
+	case opts.Warning != nil && opts.Warning.DoesViolate(total.Seconds()):
+		state = check.WorstState(state, check.Warning)
+	}
+
+	pr.AddPerfdata(opts.timePerfdata(total))
+
+	pr.SetState(state)
+	pr.SetOutput(output)
+
+	return pr
+}
+
+// readResponse reads from the connection until the pattern matches, the connection is closed
+// or the maximum size is reached. An error is only returned if the pattern did not match
+// and the connection failed, e.g. due to a timeout.
+func readResponse(conn net.Conn, expect *regexp.Regexp, maxSize int) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxResponseSize
+	}
+
+	response := make([]byte, 0, maxSize)
+	buf := make([]byte, maxSize)
+
+	for len(response) < maxSize {
+		n, err := conn.Read(buf[:maxSize-len(response)])
+		response = append(response, buf[:n]...)
+
+		if expect.Match(response) || errors.Is(err, io.EOF) {
+			return response, nil
+		}
+
+		if err != nil {
+			return response, err
+		}
+	}
+
+	return response, nil
+}
+
+// classifyError maps a connection error to a Failure
+func classifyError(err error) Failure {
+	var (
+		dnsErr *net.DNSError
+		netErr net.Error
+	)
+
+	switch {
+	case errors.As(err, &dnsErr):
+		return FailureDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return FailureRefused
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded):
+		return FailureTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return FailureTimeout
+	default:
+		return FailureError
+	}
+}
+
+// truncate returns at most n bytes of the response as string
+func truncate(b []byte, n int) string {
+	if len(b) > n {
+		return string(b[:n]) + "..."
+	}
+
+	return string(b)
+}
@@ -0,0 +1,266 @@
+package probe
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NETWAYS/go-check"
+	"github.com/NETWAYS/go-check/result"
+)
+
+// newTestListener accepts TCP connections and handles them with f
+func newTestListener(t *testing.T, f func(conn net.Conn)) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				f(conn)
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestPort(t *testing.T) {
+	banner := newTestListener(t, func(conn net.Conn) {
+		_, _ = conn.Write([]byte("SSH-2.0-OpenSSH_9.6\r\n"))
+	})
+
+	echo := newTestListener(t, func(conn net.Conn) {
+		buf := make([]byte, 64)
+		n, _ := conn.Read(buf)
+		_, _ = conn.Write(buf[:n])
+	})
+
+	silent := newTestListener(t, func(conn net.Conn) {
+		time.Sleep(200 * time.Millisecond)
+	})
+
+	// Get a free port, that refuses connections
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	refused := closed.Addr().String()
+	closed.Close()
+
+	testcases := map[string]struct {
+		opts     PortOptions
+		expected check.Status
+		output   string
+	}{
+		"connect": {
+			opts:     PortOptions{Address: banner},
+			expected: check.OK,
+			output:   "TCP " + banner + ": connected in",
+		},
+		"banner": {
+			opts:     PortOptions{Address: banner, Name: "ssh", Expect: regexp.MustCompile(`^SSH-2\.0`)},
+			expected: check.OK,
+			output:   "ssh: connected in",
+		},
+		"send-expect": {
+			opts:     PortOptions{Address: echo, Send: "PING\r\n", Expect: regexp.MustCompile(`PING`)},
+			expected: check.OK,
+			output:   "response matched",
+		},
+		"mismatch": {
+			opts:     PortOptions{Address: banner, Expect: regexp.MustCompile(`^HTTP`)},
+			expected: check.Warning,
+			output:   "does not match",
+		},
+		"mismatch-state": {
+			opts: PortOptions{
+				Address:       banner,
+				Expect:        regexp.MustCompile(`^HTTP`),
+				FailureStates: map[Failure]check.Status{FailureMismatch: check.Critical},
+			},
+			expected: check.Critical,
+			output:   "does not match",
+		},
+		"refused": {
+			opts:     PortOptions{Address: refused},
+			expected: check.Critical,
+			output:   "connection failed",
+		},
+		"refused-state": {
+			opts:     PortOptions{Address: refused, FailureStates: map[Failure]check.Status{FailureRefused: check.OK}},
+			expected: check.OK,
+			output:   "connection failed",
+		},
+		"timeout": {
+			opts: PortOptions{
+				Address:       silent,
+				Timeout:       20 * time.Millisecond,
+				Expect:        regexp.MustCompile(`.`),
+				FailureStates: map[Failure]check.Status{FailureTimeout: check.Warning},
+			},
+			expected: check.Warning,
+			output:   "no matching response",
+		},
+		"dns": {
+			opts:     PortOptions{Address: "host.invalid:22", Timeout: time.Second},
+			expected: check.Unknown,
+			output:   "connection failed",
+		},
+		"threshold": {
+			opts:     PortOptions{Address: banner, Critical: &check.Threshold{Lower: 10, Upper: check.PosInf}},
+			expected: check.Critical,
+			output:   "connected in",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			pr := Port(context.Background(), tc.opts)
+			output := getOutput(pr)
+
+			if pr.GetStatus() != tc.expected {
+				t.Fatalf("expected %v, got %v: %s", tc.expected, pr.GetStatus(), output)
+			}
+
+			if !strings.Contains(output, tc.output) {
+				t.Fatalf("expected %q in output, got %q", tc.output, output)
+			}
+
+			// The total time is also emitted for failures
+			if !strings.Contains(output, "_time=") {
+				t.Fatalf("expected time perfdata in output, got %q", output)
+			}
+		})
+	}
+}
+
+func TestPort_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer conn.Close()
+
+	go func() {
+		buf := make([]byte, 64)
+
+		n, addr, err := conn.ReadFrom(buf)
+		if err == nil {
+			_, _ = conn.WriteTo(buf[:n], addr)
+		}
+	}()
+
+	pr := Port(context.Background(), PortOptions{
+		Network: "udp",
+		Address: conn.LocalAddr().String(),
+		Timeout: time.Second,
+		Send:    "ping",
+		Expect:  regexp.MustCompile(`ping`),
+	})
+
+	if pr.GetStatus() != check.OK {
+		t.Fatalf("expected %v, got %v: %s", check.OK, pr.GetStatus(), getOutput(pr))
+	}
+
+	if !strings.Contains(getOutput(pr), "|udp_127.0.0.1_") {
+		t.Fatalf("expected the address as perfdata prefix, got %q", getOutput(pr))
+	}
+
+	// Without a response a closed port can not be detected
+	pr = Port(context.Background(), PortOptions{Network: "udp", Address: conn.LocalAddr().String()})
+
+	if pr.GetStatus() != check.Unknown {
+		t.Fatalf("expected %v, got %v: %s", check.Unknown, pr.GetStatus(), getOutput(pr))
+	}
+}
+
+func TestPort_UDPRefused(t *testing.T) {
+	// Get a free port, that is closed
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	address := conn.LocalAddr().String()
+	conn.Close()
+
+	pr := Port(context.Background(), PortOptions{
+		Network: "udp",
+		Address: address,
+		Timeout: time.Second,
+		Send:    "ping",
+		Expect:  regexp.MustCompile(`ping`),
+	})
+
+	if pr.GetStatus() != check.Critical {
+		t.Fatalf("expected %v, got %v: %s", check.Critical, pr.GetStatus(), getOutput(pr))
+	}
+}
+
+func TestPort_Concurrent(t *testing.T) {
+	address := newTestListener(t, func(net.Conn) {})
+
+	var (
+		overall result.Overall
+		wg      sync.WaitGroup
+	)
+
+	for _, name := range []string{"a", "b", "c"} {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			overall.AddSubcheck(Port(context.Background(), PortOptions{Address: address, Name: name}))
+		}()
+	}
+
+	wg.Wait()
+
+	if overall.GetStatus() != check.OK {
+		t.Fatalf("expected %v, got %v", check.OK, overall.GetStatus())
+	}
+
+	for _, label := range []string{"a_time=", "b_time=", "c_time="} {
+		if !strings.Contains(overall.GetOutput(), label) {
+			t.Fatalf("expected %q in output, got %q", label, overall.GetOutput())
+		}
+	}
+}
+
+func TestPortOptions_label(t *testing.T) {
+	testcases := map[string]struct {
+		opts     PortOptions
+		expected string
+	}{
+		"name":    {opts: PortOptions{Address: "localhost:22", Name: "ssh"}, expected: "ssh_time"},
+		"address": {opts: PortOptions{Address: "localhost:22"}, expected: "localhost_22_time"},
+		"ipv6":    {opts: PortOptions{Address: "[::1]:22"}, expected: "__1_22_time"},
+		"udp":     {opts: PortOptions{Network: "udp", Address: "localhost:53"}, expected: "udp_localhost_53_time"},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			if tc.opts.label("time") != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, tc.opts.label("time"))
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package result
+
+import (
+	"github.com/NETWAYS/go-check"
+)
+
+// Member is a PartialResult as seen by an Aggregation
+type Member struct {
+	Status check.Status
+	// Weight of the PartialResult, 1 unless set with SetWeight
+	Weight float64
+}
+
+// Aggregation derives a single state from the states of the PartialResults
+// of an Overall or a PartialResult
+type Aggregation interface {
+	Aggregate(members []Member) check.Status
+}
+
+// summarizer is implemented by Aggregations that provide a summary of the members for the output
+type summarizer interface {
+	Summary(states ...check.Status) string
+}
+
+// AggregationFunc allows to use a custom function as Aggregation
+type AggregationFunc func(members []Member) check.Status
+
+// Aggregate calls f(members)
+func (f AggregationFunc) Aggregate(members []Member) check.Status {
+	return f(members)
+}
+
+var (
+	// WorstAggregation returns the worst state of all members, see check.WorstState.
+	// This is the default.
+	WorstAggregation Aggregation = AggregationFunc(func(members []Member) check.Status {
+		return check.WorstState(memberStates(members)...)
+	})
+
+	// BestAggregation returns the best state of all members,
+	// e.g. for checks where any one of multiple paths has to succeed
+	BestAggregation Aggregation = AggregationFunc(func(members []Member) check.Status {
+		if len(members) == 0 {
+			return check.Unknown
+		}
+
+		best := members[0].Status
+
+		for _, m := range members[1:] {
+			if check.Compare(best, m.Status) < 0 {
+				best = m.Status
+			}
+		}
+
+		return best
+	})
+
+	// MajorityAggregation returns the state with the highest total weight of members.
+	// On a tie the worse state wins.
+	MajorityAggregation Aggregation = AggregationFunc(func(members []Member) check.Status {
+		if len(members) == 0 {
+			return check.Unknown
+		}
+
+		weights := map[check.Status]float64{}
+
+		for _, m := range members {
+			weights[m.Status] += m.Weight
+		}
+
+		majority := members[0].Status
+
+		for state, weight := range weights {
+			if weight > weights[majority] || (weight == weights[majority] && check.Compare(majority, state) > 0) {
+				majority = state
+			}
+		}
+
+		return majority
+	})
+
+	// UnknownIsOKAggregation returns the worst state of all members, while Unknown is treated as OK
+	UnknownIsOKAggregation Aggregation = AggregationFunc(func(members []Member) check.Status {
+		states := memberStates(members)
+
+		for i := range states {
+			if states[i] == check.Unknown {
+				states[i] = check.OK
+			}
+		}
+
+		return check.WorstState(states...)
+	})
+)
+
+// Weighted derives the state from the share of the total weight of members which are not OK.
+//
+//	Weighted{WarningAbove: 0.25, CriticalAbove: 0.5}
+//
+// is warning if more than a quarter and critical if more than half of the weight is not OK.
+type Weighted struct {
+	WarningAbove  float64
+	CriticalAbove float64
+}
+
+// Aggregate returns the state for the given members.
+// Without any members or weight the state is Unknown.
+func (w Weighted) Aggregate(members []Member) check.Status {
+	var total, failed float64
+
+	for _, m := range members {
+		total += m.Weight
+
+		if m.Status != check.OK {
+			failed += m.Weight
+		}
+	}
+
+	if total <= 0 {
+		return check.Unknown
+	}
+
+	share := failed / total
+
+	switch {
+	case share > w.CriticalAbove:
+		return check.Critical
+	case share > w.WarningAbove:
+		return check.Warning
+	default:
+		return check.OK
+	}
+}
+
+// Aggregate returns the state for the given members, see Evaluate
+func (q Quorum) Aggregate(members []Member) check.Status {
+	return q.Evaluate(memberStates(members)...)
+}
+
+// memberStates returns the states of all members
+func memberStates(members []Member) []check.Status {
+	states := make([]check.Status, len(members))
+
+	for i, m := range members {
+		states[i] = m.Status
+	}
+
+	return states
+}
@@ -0,0 +1,146 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/NETWAYS/go-check"
+)
+
+func members(states ...check.Status) []Member {
+	result := make([]Member, len(states))
+
+	for i, state := range states {
+		result[i] = Member{Status: state, Weight: 1}
+	}
+
+	return result
+}
+
+func TestAggregations(t *testing.T) {
+	testcases := map[string]struct {
+		aggregation Aggregation
+		members     []Member
+		expected    check.Status
+	}{
+		"worst": {
+			aggregation: WorstAggregation,
+			members:     members(check.OK, check.Warning, check.Critical),
+			expected:    check.Critical,
+		},
+		"best": {
+			aggregation: BestAggregation,
+			members:     members(check.Critical, check.Warning, check.Unknown),
+			expected:    check.Warning,
+		},
+		"best-empty": {
+			aggregation: BestAggregation,
+			members:     members(),
+			expected:    check.Unknown,
+		},
+		"majority": {
+			aggregation: MajorityAggregation,
+			members:     members(check.OK, check.Critical, check.OK),
+			expected:    check.OK,
+		},
+		"majority-tie": {
+			aggregation: MajorityAggregation,
+			members:     members(check.OK, check.Warning, check.Warning, check.OK),
+			expected:    check.Warning,
+		},
+		"majority-weighted": {
+			aggregation: MajorityAggregation,
+			members:     []Member{{check.OK, 1}, {check.OK, 1}, {check.Critical, 3}},
+			expected:    check.Critical,
+		},
+		"unknown-is-ok": {
+			aggregation: UnknownIsOKAggregation,
+			members:     members(check.OK, check.Unknown),
+			expected:    check.OK,
+		},
+		"unknown-is-ok-warning": {
+			aggregation: UnknownIsOKAggregation,
+			members:     members(check.Warning, check.Unknown),
+			expected:    check.Warning,
+		},
+		"weighted-ok": {
+			aggregation: Weighted{WarningAbove: 0.25, CriticalAbove: 0.5},
+			members:     []Member{{check.OK, 3}, {check.Critical, 1}},
+			expected:    check.OK,
+		},
+		"weighted-critical": {
+			aggregation: Weighted{WarningAbove: 0.25, CriticalAbove: 0.5},
+			members:     []Member{{check.OK, 1}, {check.Critical, 2}},
+			expected:    check.Critical,
+		},
+		"weighted-empty": {
+			aggregation: Weighted{},
+			members:     members(),
+			expected:    check.Unknown,
+		},
+		"custom": {
+			aggregation: AggregationFunc(func([]Member) check.Status { return check.Warning }),
+			members:     members(check.OK),
+			expected:    check.Warning,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			actual := tc.aggregation.Aggregate(tc.members)
+
+			if actual != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPartialResult_SetAggregation(t *testing.T) {
+	var overall Overall
+
+	paths := NewPartialResult()
+	paths.SetOutput("Redundant paths")
+	paths.SetAggregation(BestAggregation)
+
+	primary := NewPartialResult()
+	primary.SetOutput("primary")
+	primary.SetState(check.Critical)
+
+	secondary := NewPartialResult()
+	secondary.SetOutput("secondary")
+	secondary.SetState(check.OK)
+
+	paths.AddSubcheck(primary)
+	paths.AddSubcheck(secondary)
+
+	overall.AddSubcheck(paths)
+
+	if overall.GetStatus() != check.OK {
+		t.Fatalf("expected %v, got %v", check.OK, overall.GetStatus())
+	}
+}
+
+func TestOverall_SetAggregation(t *testing.T) {
+	var overall Overall
+
+	overall.SetAggregation(Weighted{WarningAbove: 0.5, CriticalAbove: 0.5})
+
+	important := NewPartialResult()
+	important.SetOutput("important")
+	important.SetState(check.Critical)
+	important.SetWeight(2)
+
+	overall.AddSubcheck(important)
+	overall.Add(check.OK, "minor 1")
+	overall.Add(check.OK, "minor 2")
+
+	if overall.GetStatus() != check.OK {
+		t.Fatalf("expected %v, got %v", check.OK, overall.GetStatus())
+	}
+
+	overall.Add(check.Unknown, "minor 3")
+
+	if overall.GetStatus() != check.Critical {
+		t.Fatalf("expected %v, got %v", check.Critical, overall.GetStatus())
+	}
+}
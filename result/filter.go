@@ -0,0 +1,121 @@
+package result
+
+import (
+	"regexp"
+)
+
+// FilterMode defines what happens to PartialResults filtered by a Filter
+type FilterMode int
+
+const (
+	// FilterDrop removes the PartialResults including their perfdata
+	FilterDrop FilterMode = iota
+	// FilterIgnore keeps the PartialResults in the output, but excludes them from the state and the perfdata
+	FilterIgnore
+)
+
+// Filter selects PartialResults by their name, see PartialResult.SetName.
+// PartialResults without a name are never filtered.
+type Filter struct {
+	// If set, only PartialResults with a name matching any of the expressions are kept.
+	// The PartialResults below a matching one are included as well, and a parent is kept
+	// as long as any PartialResult below it is included.
+	Include []*regexp.Regexp
+	// PartialResults with a name matching any of the expressions are filtered, including everything below.
+	// A PartialResult without an explicit state is filtered as well, if all PartialResults below it are filtered.
+	Exclude []*regexp.Regexp
+	Mode    FilterMode
+}
+
+// ApplyFilter drops or ignores all PartialResults not selected by the Filter.
+// The number of filtered PartialResults is reported in the output.
+// ApplyFilter should be called after all PartialResults have been added.
+func (o *Overall) ApplyFilter(filter Filter) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var filtered int
+
+	o.partialResults, filtered, _ = filter.apply(o.partialResults, len(filter.Include) == 0)
+	o.filtered += filtered
+}
+
+// apply filters the PartialResults recursively. included is true if a parent matched Include.
+// Returns the remaining PartialResults, the number of filtered ones and
+// if any named PartialResult was included.
+func (f Filter) apply(partialResults []*PartialResult, included bool) ([]*PartialResult, int, bool) {
+	var (
+		kept        = make([]*PartialResult, 0, len(partialResults))
+		filtered    int
+		anyIncluded bool
+	)
+
+	for _, sc := range partialResults {
+		sc.mu.Lock()
+
+		// Already ignored by a previous Filter
+		if sc.ignored {
+			kept = append(kept, sc)
+
+			sc.mu.Unlock()
+
+			continue
+		}
+
+		name := sc.name
+		selfIncluded := included || (name != "" && matchesAny(f.Include, name))
+
+		keep := name == "" || !matchesAny(f.Exclude, name)
+
+		if keep {
+			var (
+				n             int
+				childIncluded bool
+			)
+
+			hadChildren := len(sc.partialResults) > 0
+
+			sc.partialResults, n, childIncluded = f.apply(sc.partialResults, selfIncluded)
+			filtered += n
+
+			switch {
+			case hadChildren && len(activeResults(sc.partialResults)) == 0 &&
+				!sc.stateSetExplicitly && !sc.defaultStateSetExplicitly:
+				// A group without a state of its own and without any remaining results would be UNKNOWN
+				keep = false
+			case name != "":
+				keep = selfIncluded || childIncluded
+				anyIncluded = anyIncluded || keep
+			default:
+				anyIncluded = anyIncluded || childIncluded
+			}
+		}
+
+		switch {
+		case keep:
+			kept = append(kept, sc)
+		case f.Mode == FilterIgnore:
+			sc.ignored = true
+			filtered++
+
+			kept = append(kept, sc)
+		default:
+			filtered++
+		}
+
+		sc.mu.Unlock()
+	}
+
+	return kept, filtered, anyIncluded
+}
+
+// matchesAny returns true if the name matches any of the expressions
+func matchesAny(expressions []*regexp.Regexp, name string) bool {
+	for _, re := range expressions {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
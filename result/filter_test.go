@@ -0,0 +1,122 @@
+package result
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/NETWAYS/go-check"
+)
+
+func TestOverall_ApplyFilter(t *testing.T) {
+	testcases := map[string]struct {
+		filter   Filter
+		status   check.Status
+		expected string
+	}{
+		"exclude": {
+			filter: Filter{Exclude: []*regexp.Regexp{regexp.MustCompile("^/boot$")}},
+			status: check.Warning,
+			expected: `Disk /var
+\_ [WARNING] Disks
+    \_ [OK] Disk /
+    \_ [WARNING] Disk /var
+\_ [OK] Unnamed
+1 subchecks filtered
+|/=1 /var=1
+`,
+		},
+		"include": {
+			filter: Filter{Include: []*regexp.Regexp{regexp.MustCompile("^/$")}},
+			status: check.OK,
+			expected: `states: ok=2
+\_ [OK] Disks
+    \_ [OK] Disk /
+\_ [OK] Unnamed
+2 subchecks filtered
+|/=1
+`,
+		},
+		"include-parent": {
+			filter: Filter{Include: []*regexp.Regexp{regexp.MustCompile("disks")}},
+			status: check.Critical,
+			expected: `Disk /boot
+\_ [CRITICAL] Disks
+    \_ [OK] Disk /
+    \_ [CRITICAL] Disk /boot
+    \_ [WARNING] Disk /var
+\_ [OK] Unnamed
+|/=1 /boot=1 /var=1
+`,
+		},
+		"ignore": {
+			filter: Filter{Exclude: []*regexp.Regexp{regexp.MustCompile("^/boot$")}, Mode: FilterIgnore},
+			status: check.Warning,
+			expected: `Disk /var
+\_ [WARNING] Disks
+    \_ [OK] Disk /
+    \_ [CRITICAL] Disk /boot (ignored)
+    \_ [WARNING] Disk /var
+\_ [OK] Unnamed
+1 subchecks filtered
+|/=1 /var=1
+`,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			o := newTestOverall(
+				testResult{name: "disks", output: "Disks", subchecks: []testResult{
+					{name: "/", state: check.OK, output: "Disk /", perfdata: []*check.Perfdata{{Label: "/", Value: 1}}},
+					{name: "/boot", state: check.Critical, output: "Disk /boot", perfdata: []*check.Perfdata{{Label: "/boot", Value: 1}}},
+					{name: "/var", state: check.Warning, output: "Disk /var", perfdata: []*check.Perfdata{{Label: "/var", Value: 1}}},
+				}},
+				testResult{state: check.OK, output: "Unnamed"},
+			)
+			o.ApplyFilter(tc.filter)
+
+			if o.GetStatus() != tc.status {
+				t.Fatalf("expected %v, got %v", tc.status, o.GetStatus())
+			}
+
+			if o.GetOutput() != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, o.GetOutput())
+			}
+		})
+	}
+}
+
+func TestOverall_ApplyFilter_EmptyGroup(t *testing.T) {
+	testcases := map[FilterMode]string{
+		FilterDrop: `states: ok=1
+\_ [OK] Root
+3 subchecks filtered
+`,
+		FilterIgnore: `states: ok=1
+\_ [UNKNOWN] Disks (ignored)
+    \_ [OK] Disk /snap/a (ignored)
+    \_ [OK] Disk /snap/b (ignored)
+\_ [OK] Root
+3 subchecks filtered
+`,
+	}
+
+	for mode, expected := range testcases {
+		o := newTestOverall(
+			testResult{name: "disks", output: "Disks", subchecks: []testResult{
+				{name: "/snap/a", state: check.OK, output: "Disk /snap/a"},
+				{name: "/snap/b", state: check.OK, output: "Disk /snap/b"},
+			}},
+			testResult{name: "/", state: check.OK, output: "Root"},
+		)
+		o.ApplyFilter(Filter{Exclude: []*regexp.Regexp{regexp.MustCompile("^/snap/")}, Mode: mode})
+
+		if o.GetStatus() != check.OK {
+			t.Fatalf("expected %v, got %v", check.OK, o.GetStatus())
+		}
+
+		if o.GetOutput() != expected {
+			t.Fatalf("expected %q, got %q", expected, o.GetOutput())
+		}
+	}
+}
@@ -3,8 +3,10 @@ package result
 
 import (
 	"fmt"
+	"io"
 	"strings"
 	"sync"
+	"text/template"
 
 	"github.com/NETWAYS/go-check"
 )
@@ -34,6 +36,34 @@ type Overall struct {
 	oKSummary string
 	// The results that are associated with this overall
 	partialResults []*PartialResult
+	// Additional diagnostic lines (e.g. log messages) appended to the long output
+	diagnostics []string
+	// Verbosity level of the output, see check.VerbositySummary and following
+	verbosity int
+	// aggregation derives the state from the PartialResults, when nil the worst state is used
+	aggregation Aggregation
+	// verbositySetExplicitly indicates that SetVerbosity was called. When false,
+	// GetOutput shows all subchecks and diagnostic lines.
+	verbositySetExplicitly bool
+	// Number of PartialResults removed or ignored by ApplyFilter
+	filtered int
+	// Templates for the summary by state, see SetSummaryTemplate
+	summaryTemplates map[check.Status]*template.Template
+	// summaryMode defines the summary of a non-OK Overall, see SetSummaryMode
+	summaryMode SummaryMode
+	// summaryLimit is the maximum number of outputs in the summary, 0 means no limit
+	summaryLimit int
+	// maxOutputLength limits the length of GetOutput, 0 means no limit
+	maxOutputLength int
+	// maxOutputLengthSetExplicitly indicates that SetMaxOutputLength was called.
+	// When false, check.MaxOutputLength is used.
+	maxOutputLengthSetExplicitly bool
+	// perfdataPrefix enables the prefixing of perfdata labels with the names of the PartialResults
+	perfdataPrefix bool
+	// strictPerfdata fails the Overall for any problem of the perfdata, see SetStrictPerfdata
+	strictPerfdata bool
+	// duplicatePerfdata defines how perfdata with the same label is handled
+	duplicatePerfdata DuplicateMode
 
 	// We use a Mutex to make sure PartialResults can be added and evaluated concurrently
 	mu sync.RWMutex
@@ -58,30 +88,38 @@ func (o *Overall) AddSubcheck(subcheck *PartialResult) {
 }
 
 // GetStatus returns the current state (ok, warning, critical, unknown) of the Overall.
+// The state is UNKNOWN for perfdata with a NaN or infinite value, if check.InvalidValues is check.InvalidValueFail,
+// or for any problem of the perfdata in strict mode, see SetStrictPerfdata.
 // GetStatus is concurrency-safe
 func (o *Overall) GetStatus() check.Status {
 	o.mu.RLock()
 	defer o.mu.RUnlock()
 
-	statuses := o.getStatusCount()
-
-	if statuses.Critical > 0 {
-		return check.Critical
-	}
-
-	if statuses.Unknown > 0 {
+	if o.getInvalidPerfdata() != nil {
 		return check.Unknown
 	}
 
-	if statuses.Warning > 0 {
-		return check.Warning
+	if o.aggregation != nil {
+		return o.aggregation.Aggregate(getMembers(activeResults(o.partialResults)))
 	}
 
-	if statuses.OK > 0 {
-		return check.OK
-	}
+	return check.WorstState(o.getStates()...)
+}
 
-	return check.Unknown
+// SetVerbosity sets the verbosity level of the output, see check.VerbositySummary and following.
+//
+//	0  only the summary
+//	1  additionally the failed subchecks
+//	2  additionally all subchecks
+//	3  additionally the diagnostic lines
+//
+// Without calling SetVerbosity all subchecks and diagnostic lines are shown.
+func (o *Overall) SetVerbosity(level int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.verbosity = level
+	o.verbositySetExplicitly = true
 }
 
 // GetOutput returns a text representation of the current outputs of the Overall.
@@ -90,29 +128,148 @@ func (o *Overall) GetOutput() string {
 	o.mu.RLock()
 	defer o.mu.RUnlock()
 
+	mode := outputAll
+	if o.verbositySetExplicitly && o.verbosity == check.VerbosityFailed {
+		mode = outputFailedOnly
+	}
+
+	text := o.getText(mode)
+	pdata := o.getPerfdataString()
+
+	maxLength := check.MaxOutputLength
+	if o.maxOutputLengthSetExplicitly {
+		maxLength = o.maxOutputLength
+	}
+
+	if maxLength > 0 && len(text)+len(pdata)+2 > maxLength {
+		reduced := false
+
+		// Reduce the long output step by step, before it is truncated
+		for _, m := range []outputMode{outputCollapseOK, outputFailedOnly} {
+			if m <= mode {
+				continue
+			}
+
+			text = o.getText(m)
+
+			if len(text)+len(check.TruncatedMarker)+1+len(pdata)+2 <= maxLength {
+				text += check.TruncatedMarker + "\n"
+				reduced = true
+
+				break
+			}
+		}
+
+		if !reduced {
+			// Leave space for the separator and line breaks, but truncate for very small limits as well
+			text, pdata = check.TruncateOutput(text, pdata, max(maxLength-3, 1))
+			text = strings.TrimSuffix(text, "\n") + "\n"
+		}
+	}
+
+	if len(pdata) > 0 {
+		text += check.PerfdataSeparatorSymbol + pdata + "\n"
+	}
+
+	return text
+}
+
+// getText returns the summary and the long output of the Overall, the caller must hold the lock
+func (o *Overall) getText(mode outputMode) string {
 	var output strings.Builder
 
 	output.WriteString(o.getSummary() + "\n")
 
-	if o.partialResults != nil {
-		var pdata strings.Builder
+	showSubchecks := !o.verbositySetExplicitly || o.verbosity >= check.VerbosityFailed
+	showDiagnostics := !o.verbositySetExplicitly || o.verbosity >= check.VerbosityDiagnostic
 
-		// Generate indeted output and perfdata for all partialResults
+	// Generate indeted output for all partialResults
+	if showSubchecks {
 		for i := range o.partialResults {
-			output.WriteString(strings.ReplaceAll(o.partialResults[i].getOutput(0), check.PerfdataSeparatorSymbol, " "))
-			pdata.WriteString(" " + o.partialResults[i].getPerfdata())
+			output.WriteString(strings.ReplaceAll(o.partialResults[i].getOutput(0, mode), check.PerfdataSeparatorSymbol, " "))
 		}
 
-		pdataString := strings.Trim(pdata.String(), " ")
+		if o.filtered > 0 {
+			fmt.Fprintf(&output, "%d subchecks filtered\n", o.filtered)
+		}
+	}
 
-		if len(pdataString) > 0 {
-			output.WriteString(check.PerfdataSeparatorSymbol + pdataString + "\n")
+	if showDiagnostics {
+		for _, line := range o.diagnostics {
+			output.WriteString(strings.ReplaceAll(line, check.PerfdataSeparatorSymbol, " ") + "\n")
 		}
 	}
 
 	return output.String()
 }
 
+// getPerfdataString returns the perfdata of all PartialResults, the caller must hold the lock
+func (o *Overall) getPerfdataString() string {
+	list := o.getPerfdataList()
+
+	return list.String()
+}
+
+// SetMaxOutputLength limits the length of GetOutput in bytes, 0 means no limit.
+// Until it is called, the global check.MaxOutputLength is used.
+//
+// If the output is too long, the subchecks below OK subchecks are omitted first, then all OK subchecks.
+// Finally the long output is truncated, while the summary and the perfdata are kept as long as possible.
+// A truncated output is marked with check.TruncatedMarker.
+func (o *Overall) SetMaxOutputLength(length int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.maxOutputLength = length
+	o.maxOutputLengthSetExplicitly = true
+}
+
+// AddDiagnostic appends lines to the long output, after the output of all subchecks.
+// AddDiagnostic is concurrency-safe
+func (o *Overall) AddDiagnostic(lines ...string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.diagnostics = append(o.diagnostics, lines...)
+}
+
+// DiagnosticWriter returns an io.Writer that adds every written line as diagnostic output,
+// e.g. to attach the messages of a check.Config Logger to the long output.
+func (o *Overall) DiagnosticWriter() io.Writer {
+	return diagnosticWriter{overall: o}
+}
+
+// diagnosticWriter splits written data into lines and adds them to an Overall
+type diagnosticWriter struct {
+	overall *Overall
+}
+
+func (w diagnosticWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	lines := strings.Split(strings.TrimRight(string(p), "\n"), "\n")
+
+	w.overall.AddDiagnostic(lines...)
+
+	return len(p), nil
+}
+
+// SetQuorum derives the state from the number of OK PartialResults instead of the worst state.
+// The summary then shows how many PartialResults are in each state.
+func (o *Overall) SetQuorum(quorum Quorum) {
+	o.SetAggregation(quorum)
+}
+
+// SetAggregation sets how the state is derived from the PartialResults, see WorstAggregation and following
+func (o *Overall) SetAggregation(aggregation Aggregation) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.aggregation = aggregation
+}
+
 // SetOKSummary sets the summary to the given string
 func (o *Overall) SetOKSummary(summary string) {
 	o.mu.Lock()
@@ -122,47 +279,55 @@ func (o *Overall) SetOKSummary(summary string) {
 }
 
 func (o *Overall) getStatusCount() statusCount {
-	result := statusCount{
-		OK:       0,
-		Warning:  0,
-		Critical: 0,
-		Unknown:  0,
-	}
-
-	if len(o.partialResults) == 0 {
-		return result
-	}
-
-	for _, sc := range o.partialResults {
-		switch sc.GetStatus() {
-		case check.Critical:
-			result.Critical++
-		case check.Warning:
-			result.Warning++
-		case check.Unknown:
-			result.Unknown++
-		case check.OK:
-			result.OK++
-		}
+	return countStates(o.getStates())
+}
+
+// getStates returns the states of all PartialResults that are not ignored
+func (o *Overall) getStates() []check.Status {
+	active := activeResults(o.partialResults)
+	states := make([]check.Status, len(active))
+
+	for i, sc := range active {
+		states[i] = sc.GetStatus()
 	}
 
-	return result
+	return states
 }
 
 // GetSummary returns a text representation of the current state of the Overall
 func (o *Overall) getSummary() string {
+	if err := o.getInvalidPerfdata(); err != nil {
+		return strings.ReplaceAll(err.Error(), "\n", ", ")
+	}
+
 	checkState := o.GetStatus()
 
+	if summary, ok := o.getTemplateSummary(checkState); ok {
+		return summary
+	}
+
 	if checkState == check.OK && o.oKSummary != "" {
 		return strings.ReplaceAll(o.oKSummary, check.PerfdataSeparatorSymbol, " ")
 	}
 
-	if len(o.partialResults) == 0 {
+	if len(activeResults(o.partialResults)) == 0 {
 		// Oh, we actually don't have those either
 		return "No status information"
 	}
 
-	if checkState == check.OK {
+	if summarizer, ok := o.aggregation.(summarizer); ok {
+		return summarizer.Summary(o.getStates()...)
+	}
+
+	if checkState == check.OK || o.summaryMode == SummaryCounts {
+		return o.getGenericSummary()
+	}
+
+	if o.summaryMode == SummaryAllFailing || o.summaryMode == SummaryGrouped {
+		if summary := o.getFailingSummary(); summary != "" {
+			return strings.ReplaceAll(summary, check.PerfdataSeparatorSymbol, " ")
+		}
+
 		return o.getGenericSummary()
 	}
 
@@ -170,7 +335,7 @@ func (o *Overall) getSummary() string {
 	worstState := check.OK
 
 	// Get the worst non-ok PartialResults output
-	for _, partRes := range o.partialResults {
+	for _, partRes := range activeResults(o.partialResults) {
 		if check.Compare(worstState, partRes.GetStatus()) > 0 {
 			result = partRes.getPartialResultFailedOutput()
 			worstState = partRes.GetStatus()
@@ -187,25 +352,14 @@ func (o *Overall) getSummary() string {
 
 func (o *Overall) getGenericSummary() string {
 	stats := o.getStatusCount()
-	result := ""
-
-	if stats.Critical > 0 {
-		result += fmt.Sprintf("critical=%d ", stats.Critical)
-	}
-
-	if stats.Unknown > 0 {
-		result += fmt.Sprintf("unknown=%d ", stats.Unknown)
-	}
+	parts := make([]string, 0, len(check.GetPrecedence()))
 
-	if stats.Warning > 0 {
-		result += fmt.Sprintf("warning=%d ", stats.Warning)
-	}
-
-	if stats.OK > 0 {
-		result += fmt.Sprintf("ok=%d ", stats.OK)
+	// Ordered from the worst to the best state
+	for _, state := range check.GetPrecedence() {
+		if count := stats.get(state); count > 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d", strings.ToLower(state.String()), count))
+		}
 	}
 
-	result = "states: " + strings.TrimSpace(result)
-
-	return result
+	return "states: " + strings.Join(parts, " ")
 }
@@ -10,6 +10,47 @@ import (
 	"github.com/NETWAYS/go-check"
 )
 
+// testResult describes a PartialResult for newTestOverall. The state is only set for results
+// without subchecks, results with subchecks derive their state.
+type testResult struct {
+	name      string
+	state     check.Status
+	output    string
+	perfdata  []*check.Perfdata
+	subchecks []testResult
+}
+
+// newTestOverall returns an Overall with the described PartialResults
+func newTestOverall(results ...testResult) *Overall {
+	o := &Overall{}
+
+	for _, r := range results {
+		o.AddSubcheck(r.build())
+	}
+
+	return o
+}
+
+func (r testResult) build() *PartialResult {
+	sc := NewPartialResult()
+	sc.SetName(r.name)
+	sc.SetOutput(r.output)
+
+	if len(r.subchecks) == 0 {
+		sc.SetState(r.state)
+	}
+
+	for _, p := range r.perfdata {
+		sc.AddPerfdata(p)
+	}
+
+	for _, sub := range r.subchecks {
+		sc.AddSubcheck(sub.build())
+	}
+
+	return sc
+}
+
 func TestOverall_AddOK(t *testing.T) {
 	overall := Overall{}
 	overall.Add(0, "test ok")
@@ -634,3 +675,105 @@ func TestOverall_Get_WithRace(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestOverall_DiagnosticWriter(t *testing.T) {
+	var overall Overall
+
+	overall.Add(check.OK, "test ok")
+
+	_, _ = fmt.Fprintf(overall.DiagnosticWriter(), "level=DEBUG msg=first\nlevel=DEBUG msg=\"a|b\"\n")
+
+	expected := "states: ok=1\n\\_ [OK] test ok\nlevel=DEBUG msg=first\nlevel=DEBUG msg=\"a b\"\n"
+
+	if expected != overall.GetOutput() {
+		t.Fatalf("expected %q, got %q", expected, overall.GetOutput())
+	}
+}
+
+func TestOverall_SetVerbosity(t *testing.T) {
+	newOverall := func() *Overall {
+		o := &Overall{}
+
+		parent := NewPartialResult()
+		parent.SetOutput("Parent")
+
+		child1 := NewPartialResult()
+		child1.SetOutput("Child OK")
+		child1.SetState(check.OK)
+
+		child2 := NewPartialResult()
+		child2.SetOutput("Child Critical")
+		child2.SetState(check.Critical)
+		child2.AddPerfdata(&check.Perfdata{Label: "foo", Value: 1})
+
+		parent.AddSubcheck(child1)
+		parent.AddSubcheck(child2)
+
+		o.AddSubcheck(parent)
+		o.Add(check.OK, "Other OK")
+		o.AddDiagnostic("level=DEBUG msg=details")
+
+		return o
+	}
+
+	testcases := map[string]struct {
+		verbosity int
+		expected  string
+	}{
+		"summary": {
+			verbosity: check.VerbositySummary,
+			expected:  "Child Critical\n|foo=1\n",
+		},
+		"failed": {
+			verbosity: check.VerbosityFailed,
+			expected:  "Child Critical\n\\_ [CRITICAL] Parent\n    \\_ [CRITICAL] Child Critical\n|foo=1\n",
+		},
+		"all": {
+			verbosity: check.VerbosityAll,
+			expected:  "Child Critical\n\\_ [CRITICAL] Parent\n    \\_ [OK] Child OK\n    \\_ [CRITICAL] Child Critical\n\\_ [OK] Other OK\n|foo=1\n",
+		},
+		"diagnostic": {
+			verbosity: check.VerbosityDiagnostic,
+			expected:  "Child Critical\n\\_ [CRITICAL] Parent\n    \\_ [OK] Child OK\n    \\_ [CRITICAL] Child Critical\n\\_ [OK] Other OK\nlevel=DEBUG msg=details\n|foo=1\n",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			o := newOverall()
+			o.SetVerbosity(tc.verbosity)
+
+			if tc.expected != o.GetOutput() {
+				t.Fatalf("expected %q, got %q", tc.expected, o.GetOutput())
+			}
+		})
+	}
+}
+
+func TestOverall_WithPrecedence(t *testing.T) {
+	if err := check.SetPrecedence(check.PrecedenceUnknownFirst); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	defer func() {
+		_ = check.SetPrecedence(check.PrecedenceCriticalFirst)
+	}()
+
+	o := Overall{}
+	o.Add(check.Critical, "Critical")
+	o.Add(check.Unknown, "Unknown")
+	o.Add(check.OK, "OK")
+
+	if o.GetStatus() != check.Unknown {
+		t.Fatalf("expected %v, got %v", check.Unknown, o.GetStatus())
+	}
+
+	if o.getSummary() != "Unknown" {
+		t.Fatalf("expected %q, got %q", "Unknown", o.getSummary())
+	}
+
+	expected := "states: unknown=1 critical=1 ok=1"
+	if o.getGenericSummary() != expected {
+		t.Fatalf("expected %q, got %q", expected, o.getGenericSummary())
+	}
+}
@@ -14,6 +14,12 @@ type PartialResult struct {
 	perfdata       check.PerfdataList
 	partialResults []*PartialResult
 	output         string
+	// name identifies the PartialResult, e.g. for a Filter
+	name string
+	// checkName is used for the perfdata in the Icinga multi-check notation, see SetCheckName
+	checkName string
+	// ignored PartialResults are shown, but excluded from the state and the perfdata, see FilterIgnore
+	ignored bool
 
 	// Result state, either set explicitly or derived from partialResults
 	state check.Status
@@ -28,6 +34,14 @@ type PartialResult struct {
 	// s.defaultState instead of check.Unknown.
 	defaultStateSetExplicitly bool
 
+	// aggregation derives the state from the PartialResults, WorstAggregation when nil
+	aggregation Aggregation
+
+	// Weight of this PartialResult for the Aggregation of its parent
+	weight float64
+	// weightSetExplicitly indicates that SetWeight was called, otherwise the weight is 1
+	weightSetExplicitly bool
+
 	mu sync.RWMutex
 }
 
@@ -58,7 +72,115 @@ func (s *PartialResult) AddPerfdata(perfdata *check.Perfdata) {
 
 // String returns the status and output of the PartialResult
 func (s *PartialResult) String() string {
-	return fmt.Sprintf("[%s] %s", s.GetStatus(), strings.ReplaceAll(s.output, check.PerfdataSeparatorSymbol, " "))
+	output := fmt.Sprintf("[%s] %s", s.GetStatus(), strings.ReplaceAll(s.getOutputLine(), check.PerfdataSeparatorSymbol, " "))
+
+	if s.isIgnored() {
+		output += " (ignored)"
+	}
+
+	return output
+}
+
+// SetName sets the name that identifies the PartialResult, e.g. a disk or an interface
+func (s *PartialResult) SetName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.name = name
+}
+
+// GetName returns the name of the PartialResult, empty if it was not set
+func (s *PartialResult) GetName() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.name
+}
+
+// SetCheckName sets the check name for the perfdata of this and all subsequent PartialResults,
+// the labels are rendered in the Icinga multi-check notation, e.g. 'disk::usage'=42%.
+// A check name of a subsequent PartialResult takes precedence.
+func (s *PartialResult) SetCheckName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkName = name
+}
+
+// isIgnored returns true if the PartialResult was ignored by a Filter
+func (s *PartialResult) isIgnored() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.ignored
+}
+
+// activeResults returns all PartialResults that are not ignored
+func activeResults(partialResults []*PartialResult) []*PartialResult {
+	active := make([]*PartialResult, 0, len(partialResults))
+
+	for _, sc := range partialResults {
+		if !sc.isIgnored() {
+			active = append(active, sc)
+		}
+	}
+
+	return active
+}
+
+// getOutputLine returns the output, extended by the member summary of the Aggregation (e.g. a Quorum)
+func (s *PartialResult) getOutputLine() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summarizer, ok := s.aggregation.(summarizer)
+	if !ok || len(s.partialResults) == 0 {
+		return s.output
+	}
+
+	summary := summarizer.Summary(s.getChildStates()...)
+
+	if s.output == "" {
+		return summary
+	}
+
+	return s.output + " (" + summary + ")"
+}
+
+// SetQuorum derives the state from the number of OK PartialResults instead of the worst state
+func (s *PartialResult) SetQuorum(quorum Quorum) {
+	s.SetAggregation(quorum)
+}
+
+// SetAggregation sets how the state is derived from the PartialResults, see WorstAggregation and following
+func (s *PartialResult) SetAggregation(aggregation Aggregation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.aggregation = aggregation
+}
+
+// SetWeight sets the weight of the PartialResult for the Aggregation of its parent, the default is 1
+func (s *PartialResult) SetWeight(weight float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.weight = weight
+	s.weightSetExplicitly = true
+}
+
+// getMember returns the PartialResult as Member for an Aggregation
+func (s *PartialResult) getMember() Member {
+	status := s.GetStatus()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.weightSetExplicitly {
+		return Member{Status: status, Weight: s.weight}
+	}
+
+	return Member{Status: status, Weight: 1}
 }
 
 // SetDefaultState sets a new default state for a PartialResult
@@ -88,7 +210,9 @@ func (s *PartialResult) GetStatus() check.Status {
 		return s.state
 	}
 
-	if len(s.partialResults) == 0 {
+	active := activeResults(s.partialResults)
+
+	if len(active) == 0 {
 		if s.defaultStateSetExplicitly {
 			return s.defaultState
 		}
@@ -96,13 +220,34 @@ func (s *PartialResult) GetStatus() check.Status {
 		return check.Unknown
 	}
 
-	states := make([]check.Status, len(s.partialResults))
+	if s.aggregation != nil {
+		return s.aggregation.Aggregate(getMembers(active))
+	}
+
+	return check.WorstState(s.getChildStates()...)
+}
+
+// getMembers returns the PartialResults as Members for an Aggregation
+func getMembers(partialResults []*PartialResult) []Member {
+	members := make([]Member, len(partialResults))
+
+	for i := range partialResults {
+		members[i] = partialResults[i].getMember()
+	}
+
+	return members
+}
+
+// getChildStates returns the states of all PartialResults that are not ignored, the caller must hold the lock
+func (s *PartialResult) getChildStates() []check.Status {
+	active := activeResults(s.partialResults)
+	states := make([]check.Status, len(active))
 
-	for i := range s.partialResults {
-		states[i] = s.partialResults[i].GetStatus()
+	for i := range active {
+		states[i] = active[i].GetStatus()
 	}
 
-	return check.WorstState(states...)
+	return states
 }
 
 // SetOutput sets the output of this PartialResult to the given string
@@ -113,34 +258,87 @@ func (s *PartialResult) SetOutput(output string) {
 	s.output = output
 }
 
-// getPerfdata returns all subsequent perfdata as a concatenated string
-func (s *PartialResult) getPerfdata() string {
-	var output strings.Builder
+// perfdataContext is passed to the subsequent PartialResults when collecting the perfdata
+type perfdataContext struct {
+	// withPrefix enables the prefixing of the labels with the names of the PartialResults
+	withPrefix bool
+	prefix     string
+	// checkName of the nearest PartialResult above
+	checkName string
+}
+
+// getPerfdataList returns the perfdata of the PartialResult and all subsequent PartialResults that are not ignored.
+// The labels are prefixed and the check names are set according to the perfdataContext.
+func (s *PartialResult) getPerfdataList(ctx perfdataContext) check.PerfdataList {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	if len(s.perfdata) > 0 {
-		output.WriteString(s.perfdata.String())
+	if ctx.withPrefix && s.name != "" {
+		ctx.prefix += s.name + PerfdataPrefixSeparator
 	}
 
-	if s.partialResults != nil {
-		for _, ss := range s.partialResults {
-			output.WriteString(" " + ss.getPerfdata())
+	if s.checkName != "" {
+		ctx.checkName = s.checkName
+	}
+
+	list := make(check.PerfdataList, 0, len(s.perfdata))
+
+	for _, p := range s.perfdata {
+		if ctx.prefix != "" || (ctx.checkName != "" && p.Check == "") {
+			// Copy the Perfdata, so the PartialResult is not modified
+			modified := *p
+			modified.Label = ctx.prefix + p.Label
+
+			if modified.Check == "" {
+				modified.Check = ctx.checkName
+			}
+
+			p = &modified
 		}
+
+		list = append(list, p)
 	}
 
-	return strings.TrimSpace(output.String())
+	for _, sc := range activeResults(s.partialResults) {
+		list = append(list, sc.getPerfdataList(ctx)...)
+	}
+
+	return list
 }
 
-// getOutput generates indented output for all subsequent PartialResults
-func (s *PartialResult) getOutput(indentLevel int) string {
+// outputMode defines which PartialResults are shown in the long output
+type outputMode int
+
+const (
+	// outputAll shows all PartialResults
+	outputAll outputMode = iota
+	// outputCollapseOK omits the PartialResults below an OK PartialResult
+	outputCollapseOK
+	// outputFailedOnly omits all OK PartialResults
+	outputFailedOnly
+)
+
+// getOutput generates indented output for all subsequent PartialResults, depending on the outputMode
+func (s *PartialResult) getOutput(indentLevel int, mode outputMode) string {
+	state := s.GetStatus()
+
+	if mode == outputFailedOnly && (state == check.OK || s.isIgnored()) {
+		return ""
+	}
+
 	var output strings.Builder
 	// The final result will look like this:
 	// [OK] Overall is OK
 	// \_ [OK] My PartialResult
 	output.WriteString(strings.Repeat("  ", indentLevel) + "\\_ " + s.String() + "\n")
 
+	if mode == outputCollapseOK && state == check.OK {
+		return output.String()
+	}
+
 	if s.partialResults != nil {
 		for _, ss := range s.partialResults {
-			output.WriteString(ss.getOutput(indentLevel + indentationOffset))
+			output.WriteString(ss.getOutput(indentLevel+indentationOffset, mode))
 		}
 	}
 
@@ -153,11 +351,16 @@ func (s *PartialResult) getPartialResultFailedOutput() string {
 		return s.output
 	}
 
+	if _, ok := s.aggregation.(summarizer); ok {
+		// The aggregation (e.g. a quorum) failed as a whole, not a single PartialResult
+		return s.getOutputLine()
+	}
+
 	result := ""
 	worstState := check.OK
 
 	// Get the worst non-ok PartialResults output
-	for _, partRes := range s.partialResults {
+	for _, partRes := range activeResults(s.partialResults) {
 		if check.Compare(worstState, partRes.GetStatus()) > 0 {
 			result = partRes.getPartialResultFailedOutput()
 			worstState = partRes.GetStatus()
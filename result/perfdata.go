@@ -0,0 +1,171 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NETWAYS/go-check"
+)
+
+// PerfdataPrefixSeparator separates the names of PartialResults in prefixed perfdata labels,
+// following the convention of Icinga for multi-checks
+const PerfdataPrefixSeparator = check.PerfdataCheckSeparator
+
+// DuplicateMode defines how perfdata with the same label is handled, see Overall.SetDuplicatePerfdata
+type DuplicateMode int
+
+const (
+	// DuplicateKeep emits all perfdata, even with the same label (default).
+	// Note that, Icinga only keeps one value of each label.
+	DuplicateKeep DuplicateMode = iota
+	// DuplicateFirst only emits the first perfdata of each label
+	DuplicateFirst
+	// DuplicateRename appends the next free counter to the label of duplicates, e.g. usage_2
+	DuplicateRename
+)
+
+// SetPerfdataPrefix enables the prefixing of perfdata labels with the names of all
+// PartialResults above, e.g. "node1::disk::usage". PartialResults without a name are skipped.
+func (o *Overall) SetPerfdataPrefix(enabled bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.perfdataPrefix = enabled
+}
+
+// SetDuplicatePerfdata sets how perfdata with the same label is handled, see DuplicateKeep and following
+func (o *Overall) SetDuplicatePerfdata(mode DuplicateMode) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.duplicatePerfdata = mode
+}
+
+// DuplicatePerfdataLabels returns all perfdata labels that occur more than once, after prefixing
+func (o *Overall) DuplicatePerfdataLabels() []string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	var duplicates []string
+
+	count := map[string]int{}
+
+	for _, p := range o.getPrefixedPerfdata() {
+		label := fullLabel(p)
+		count[label]++
+
+		if count[label] == 2 {
+			duplicates = append(duplicates, label)
+		}
+	}
+
+	return duplicates
+}
+
+// getPerfdataList returns the perfdata of all PartialResults with the duplicates handled, the caller must hold the lock
+func (o *Overall) getPerfdataList() check.PerfdataList {
+	list := o.getPrefixedPerfdata()
+
+	if o.duplicatePerfdata == DuplicateKeep {
+		return list
+	}
+
+	result := make(check.PerfdataList, 0, len(list))
+	emitted := map[string]bool{}
+
+	// Renamed labels must not collide with any other label, e.g. an existing "usage_2"
+	taken := map[string]bool{}
+	for _, p := range list {
+		taken[fullLabel(p)] = true
+	}
+
+	for _, p := range list {
+		if label := fullLabel(p); emitted[label] {
+			if o.duplicatePerfdata == DuplicateFirst {
+				continue
+			}
+
+			renamed := *p
+
+			for n := 2; ; n++ {
+				renamed.Label = fmt.Sprintf("%s_%d", p.Label, n)
+				if !taken[fullLabel(&renamed)] {
+					break
+				}
+			}
+
+			taken[fullLabel(&renamed)] = true
+			p = &renamed
+		}
+
+		emitted[fullLabel(p)] = true
+		result = append(result, p)
+	}
+
+	return result
+}
+
+// getPrefixedPerfdata returns the perfdata of all PartialResults, the caller must hold the lock
+func (o *Overall) getPrefixedPerfdata() check.PerfdataList {
+	var list check.PerfdataList
+
+	for _, sc := range activeResults(o.partialResults) {
+		list = append(list, sc.getPerfdataList(perfdataContext{withPrefix: o.perfdataPrefix})...)
+	}
+
+	return list
+}
+
+// fullLabel returns the label including the check name, see check.Perfdata.Check
+func fullLabel(p *check.Perfdata) string {
+	if p.Check == "" {
+		return p.Label
+	}
+
+	return p.Check + check.PerfdataCheckSeparator + p.Label
+}
+
+// SetStrictPerfdata enables the strict validation of the perfdata, see check.PerfdataList.Validate.
+// If any perfdata has a problem, the state of the Overall is UNKNOWN and the problems are shown in the summary.
+func (o *Overall) SetStrictPerfdata(enabled bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.strictPerfdata = enabled
+}
+
+// ValidatePerfdata returns all problems of the perfdata of all PartialResults, after prefixing
+// and handling duplicates, see check.PerfdataList.Validate
+func (o *Overall) ValidatePerfdata() error {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	list := o.getPerfdataList()
+
+	return list.Validate()
+}
+
+// getInvalidPerfdata returns the problems of the perfdata, which fail the Overall.
+// These are all problems in strict mode, otherwise NaN or infinite values, if check.InvalidValues
+// is check.InvalidValueFail. The caller must hold the lock.
+func (o *Overall) getInvalidPerfdata() error {
+	if !o.strictPerfdata && check.InvalidValues != check.InvalidValueFail {
+		return nil
+	}
+
+	list := o.getPerfdataList()
+
+	if o.strictPerfdata {
+		return list.Validate()
+	}
+
+	var errs []error
+
+	for _, p := range list {
+		if _, err := p.ValidatedString(); check.IsInvalidValue(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
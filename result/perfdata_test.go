@@ -0,0 +1,204 @@
+package result
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/NETWAYS/go-check"
+)
+
+func TestOverall_SetPerfdataPrefix(t *testing.T) {
+	o := newTestOverall(
+		testResult{name: "node1", perfdata: []*check.Perfdata{{Label: "load", Value: 1}}, subchecks: []testResult{
+			{name: "disk", state: check.OK, perfdata: []*check.Perfdata{{Label: "usage", Value: 42, Uom: "%"}}},
+		}},
+		testResult{name: "node2", perfdata: []*check.Perfdata{{Label: "load", Value: 1}}, subchecks: []testResult{
+			{name: "disk", state: check.OK, perfdata: []*check.Perfdata{{Label: "usage", Value: 42, Uom: "%"}}},
+		}},
+		// Without a name
+		testResult{state: check.OK, perfdata: []*check.Perfdata{{Label: "usage", Value: 10, Uom: "%"}}},
+	)
+
+	if labels := o.DuplicatePerfdataLabels(); strings.Join(labels, ",") != "load,usage" {
+		t.Fatalf("expected %v, got %v", "load,usage", labels)
+	}
+
+	o.SetPerfdataPrefix(true)
+
+	expected := "node1::load=1 node1::disk::usage=42% node2::load=1 node2::disk::usage=42% usage=10%"
+
+	if o.getPerfdataString() != expected {
+		t.Fatalf("expected %q, got %q", expected, o.getPerfdataString())
+	}
+
+	if labels := o.DuplicatePerfdataLabels(); len(labels) != 0 {
+		t.Fatalf("expected no duplicates, got %v", labels)
+	}
+
+	// The PartialResults are not modified
+	if o.Find("node1/disk").Perfdata()[0].Label != "usage" {
+		t.Fatalf("expected %v, got %v", "usage", o.Find("node1/disk").Perfdata()[0].Label)
+	}
+}
+
+func TestOverall_SetDuplicatePerfdata(t *testing.T) {
+	testcases := map[DuplicateMode]string{
+		DuplicateKeep:   "load=1 usage=42% load=1 usage=42% usage=10%",
+		DuplicateFirst:  "load=1 usage=42%",
+		DuplicateRename: "load=1 usage=42% load_2=1 usage_2=42% usage_3=10%",
+	}
+
+	for mode, expected := range testcases {
+		o := newTestOverall(
+			testResult{name: "node1", perfdata: []*check.Perfdata{{Label: "load", Value: 1}}, subchecks: []testResult{
+				{name: "disk", state: check.OK, perfdata: []*check.Perfdata{{Label: "usage", Value: 42, Uom: "%"}}},
+			}},
+			testResult{name: "node2", perfdata: []*check.Perfdata{{Label: "load", Value: 1}}, subchecks: []testResult{
+				{name: "disk", state: check.OK, perfdata: []*check.Perfdata{{Label: "usage", Value: 42, Uom: "%"}}},
+			}},
+			// Without a name
+			testResult{state: check.OK, perfdata: []*check.Perfdata{{Label: "usage", Value: 10, Uom: "%"}}},
+		)
+		o.SetDuplicatePerfdata(mode)
+
+		if o.getPerfdataString() != expected {
+			t.Fatalf("expected %q, got %q", expected, o.getPerfdataString())
+		}
+	}
+}
+
+func TestPartialResult_SetCheckName(t *testing.T) {
+	o := Overall{}
+
+	disk := NewPartialResult()
+	disk.SetState(check.OK)
+	disk.SetCheckName("disk")
+	disk.AddPerfdata(&check.Perfdata{Label: "usage", Value: 42, Uom: "%"})
+
+	inode := NewPartialResult()
+	inode.SetState(check.OK)
+	inode.AddPerfdata(&check.Perfdata{Label: "usage", Value: 10, Uom: "%"})
+	inode.AddPerfdata(&check.Perfdata{Label: "free", Value: 1, Check: "custom"})
+	disk.AddSubcheck(inode)
+
+	memory := NewPartialResult()
+	memory.SetState(check.OK)
+	memory.SetCheckName("memory")
+	memory.AddPerfdata(&check.Perfdata{Label: "usage", Value: 80, Uom: "%"})
+
+	o.AddSubcheck(disk)
+	o.AddSubcheck(memory)
+
+	expected := "disk::usage=42% disk::usage=10% custom::free=1 memory::usage=80%"
+	if o.getPerfdataString() != expected {
+		t.Fatalf("expected %q, got %q", expected, o.getPerfdataString())
+	}
+
+	if labels := o.DuplicatePerfdataLabels(); strings.Join(labels, ",") != "disk::usage" {
+		t.Fatalf("expected %v, got %v", "disk::usage", labels)
+	}
+}
+
+func TestOverall_InvalidPerfdataValues(t *testing.T) {
+	o := Overall{}
+
+	sc := NewPartialResult()
+	sc.SetState(check.OK)
+	sc.SetOutput("all fine")
+	sc.AddPerfdata(&check.Perfdata{Label: "time", Value: math.NaN(), Uom: "s"})
+	sc.AddPerfdata(&check.Perfdata{Label: "load", Value: 1})
+	o.AddSubcheck(sc)
+
+	// Dropped by default
+	if o.GetStatus() != check.OK || o.getPerfdataString() != "load=1" {
+		t.Fatalf("expected %v and %v, got %v and %v", check.OK, "load=1", o.GetStatus(), o.getPerfdataString())
+	}
+
+	defer func() {
+		check.InvalidValues = check.InvalidValueDrop
+	}()
+
+	check.InvalidValues = check.InvalidValueUnknown
+
+	if o.GetStatus() != check.OK || o.getPerfdataString() != "time=U load=1" {
+		t.Fatalf("expected %v and %v, got %v and %v", check.OK, "time=U load=1", o.GetStatus(), o.getPerfdataString())
+	}
+
+	check.InvalidValues = check.InvalidValueFail
+
+	if o.GetStatus() != check.Unknown {
+		t.Fatalf("expected %v, got %v", check.Unknown, o.GetStatus())
+	}
+
+	expected := "invalid perfdata value for time: perfdata value is not a number"
+
+	if o.getSummary() != expected {
+		t.Fatalf("expected %q, got %q", expected, o.getSummary())
+	}
+}
+
+func TestOverall_SetStrictPerfdata(t *testing.T) {
+	o := newTestOverall(
+		testResult{name: "node1", perfdata: []*check.Perfdata{{Label: "load", Value: 1}}, subchecks: []testResult{
+			{name: "disk", state: check.OK, perfdata: []*check.Perfdata{{Label: "usage", Value: 42, Uom: "%"}}},
+		}},
+		testResult{name: "node2", perfdata: []*check.Perfdata{{Label: "load", Value: 1}}, subchecks: []testResult{
+			{name: "disk", state: check.OK, perfdata: []*check.Perfdata{{Label: "usage", Value: 42, Uom: "%"}}},
+		}},
+		// Without a name
+		testResult{state: check.OK, perfdata: []*check.Perfdata{{Label: "usage", Value: 10, Uom: "%"}}},
+	)
+
+	if err := o.ValidatePerfdata(); !errors.Is(err, check.ErrPerfdataDuplicate) {
+		t.Fatalf("expected %v, got %v", check.ErrPerfdataDuplicate, err)
+	}
+
+	// Not failed without strict mode
+	if o.GetStatus() != check.OK {
+		t.Fatalf("expected %v, got %v", check.OK, o.GetStatus())
+	}
+
+	o.SetStrictPerfdata(true)
+
+	if o.GetStatus() != check.Unknown {
+		t.Fatalf("expected %v, got %v", check.Unknown, o.GetStatus())
+	}
+
+	expected := "perfdata load: duplicate perfdata label, perfdata usage: duplicate perfdata label"
+
+	if o.getSummary() != expected {
+		t.Fatalf("expected %q, got %q", expected, o.getSummary())
+	}
+
+	o.SetPerfdataPrefix(true)
+
+	if err := o.ValidatePerfdata(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if o.GetStatus() != check.OK {
+		t.Fatalf("expected %v, got %v", check.OK, o.GetStatus())
+	}
+}
+
+func TestOverall_SetDuplicatePerfdata_Collision(t *testing.T) {
+	o := newTestOverall(testResult{state: check.OK, perfdata: []*check.Perfdata{
+		{Label: "u", Value: 1},
+		{Label: "u", Value: 2},
+		{Label: "u_2", Value: 3},
+		{Label: "u_2", Value: 4},
+	}})
+	o.SetDuplicatePerfdata(DuplicateRename)
+
+	expected := "u=1 u_3=2 u_2=3 u_2_2=4"
+
+	if o.getPerfdataString() != expected {
+		t.Fatalf("expected %q, got %q", expected, o.getPerfdataString())
+	}
+
+	if err := o.ValidatePerfdata(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package result
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NETWAYS/go-check"
+)
+
+// Quorum derives a state from the number of OK members instead of the worst state,
+// e.g. a cluster is OK as long as at least N of M nodes are OK.
+//
+//	Quorum{WarningBelow: 3, CriticalBelow: 2}
+//
+// is warning if fewer than 3 and critical if fewer than 2 members are OK.
+// A limit of 0 is never reached.
+type Quorum struct {
+	WarningBelow  int
+	CriticalBelow int
+}
+
+// Evaluate returns the state for the given member states.
+// Without any members the state is Unknown.
+func (q Quorum) Evaluate(states ...check.Status) check.Status {
+	if len(states) == 0 {
+		return check.Unknown
+	}
+
+	ok := countStates(states).OK
+
+	switch {
+	case ok < q.CriticalBelow:
+		return check.Critical
+	case ok < q.WarningBelow:
+		return check.Warning
+	default:
+		return check.OK
+	}
+}
+
+// Summary returns how many members are in each state, e.g. "2 of 3 OK, critical=1"
+func (q Quorum) Summary(states ...check.Status) string {
+	counts := countStates(states)
+
+	summary := fmt.Sprintf("%d of %d OK", counts.OK, len(states))
+
+	if others := counts.nonOK(); others != "" {
+		summary += ", " + others
+	}
+
+	return summary
+}
+
+// countStates counts the occurrences of each state
+func countStates(states []check.Status) statusCount {
+	var result statusCount
+
+	for _, state := range states {
+		switch state {
+		case check.Critical:
+			result.Critical++
+		case check.Warning:
+			result.Warning++
+		case check.Unknown:
+			result.Unknown++
+		case check.OK:
+			result.OK++
+		}
+	}
+
+	return result
+}
+
+// get returns the count of the given state
+func (c statusCount) get(state check.Status) int {
+	switch state {
+	case check.OK:
+		return c.OK
+	case check.Warning:
+		return c.Warning
+	case check.Critical:
+		return c.Critical
+	case check.Unknown:
+		return c.Unknown
+	default:
+		return 0
+	}
+}
+
+// nonOK returns the counts of all non-OK states ordered by the precedence, e.g. "critical=1 warning=2"
+func (c statusCount) nonOK() string {
+	var parts []string
+
+	for _, state := range check.GetPrecedence() {
+		if count := c.get(state); state != check.OK && count > 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d", strings.ToLower(state.String()), count))
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,97 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/NETWAYS/go-check"
+)
+
+func TestQuorum_Evaluate(t *testing.T) {
+	quorum := Quorum{WarningBelow: 3, CriticalBelow: 2}
+
+	testcases := map[string]struct {
+		input    []check.Status
+		expected check.Status
+	}{
+		"all-ok": {
+			input:    []check.Status{check.OK, check.OK, check.OK},
+			expected: check.OK,
+		},
+		"quorum-with-failures": {
+			input:    []check.Status{check.OK, check.OK, check.OK, check.Critical},
+			expected: check.OK,
+		},
+		"warning": {
+			input:    []check.Status{check.OK, check.OK, check.Critical},
+			expected: check.Warning,
+		},
+		"critical": {
+			input:    []check.Status{check.OK, check.Unknown, check.Critical},
+			expected: check.Critical,
+		},
+		"empty": {
+			input:    []check.Status{},
+			expected: check.Unknown,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			actual := quorum.Evaluate(tc.input...)
+
+			if actual != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPartialResult_SetQuorum(t *testing.T) {
+	var overall Overall
+
+	cluster := NewPartialResult()
+	cluster.SetOutput("Cluster nodes")
+	cluster.SetQuorum(Quorum{WarningBelow: 3, CriticalBelow: 2})
+
+	for _, state := range []check.Status{check.OK, check.OK, check.Critical} {
+		node := NewPartialResult()
+		node.SetOutput("node " + state.String())
+		node.SetState(state)
+		cluster.AddSubcheck(node)
+	}
+
+	overall.AddSubcheck(cluster)
+
+	if overall.GetStatus() != check.Warning {
+		t.Fatalf("expected %v, got %v", check.Warning, overall.GetStatus())
+	}
+
+	expected := `Cluster nodes (2 of 3 OK, critical=1)
+\_ [WARNING] Cluster nodes (2 of 3 OK, critical=1)
+    \_ [OK] node OK
+    \_ [OK] node OK
+    \_ [CRITICAL] node CRITICAL
+`
+
+	if overall.GetOutput() != expected {
+		t.Fatalf("expected %q, got %q", expected, overall.GetOutput())
+	}
+}
+
+func TestOverall_SetQuorum(t *testing.T) {
+	var overall Overall
+
+	overall.SetQuorum(Quorum{CriticalBelow: 1})
+	overall.Add(check.Critical, "path 1")
+	overall.Add(check.OK, "path 2")
+
+	if overall.GetStatus() != check.OK {
+		t.Fatalf("expected %v, got %v", check.OK, overall.GetStatus())
+	}
+
+	expected := "1 of 2 OK, critical=1\n\\_ [CRITICAL] path 1\n\\_ [OK] path 2\n"
+
+	if overall.GetOutput() != expected {
+		t.Fatalf("expected %q, got %q", expected, overall.GetOutput())
+	}
+}
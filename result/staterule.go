@@ -0,0 +1,59 @@
+package result
+
+import (
+	"fmt"
+
+	"github.com/NETWAYS/go-check"
+)
+
+// ApplyStateRules replaces the state of all matching PartialResults, see check.StateRule.
+//
+// Nested PartialResults are evaluated first, so the derived states of their parents follow the new states.
+// Only the first matching rule is applied to a PartialResult and its output is annotated with the
+// replaced state, e.g. "Disk /boot (WARNING overridden by rule)".
+// ApplyStateRules should be called after all PartialResults have been added.
+func (o *Overall) ApplyStateRules(rules ...*check.StateRule) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	for _, sc := range o.partialResults {
+		sc.applyStateRules(rules)
+	}
+}
+
+// applyStateRules applies the first matching rule to the PartialResult after its PartialResults
+func (s *PartialResult) applyStateRules(rules []*check.StateRule) {
+	s.mu.RLock()
+	children := s.partialResults
+	s.mu.RUnlock()
+
+	for _, sc := range children {
+		sc.applyStateRules(rules)
+	}
+
+	state := s.GetStatus()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subjects := make([]string, 0, len(s.perfdata)+1)
+	subjects = append(subjects, s.output)
+
+	for _, p := range s.perfdata {
+		subjects = append(subjects, p.Label)
+	}
+
+	for _, rule := range rules {
+		if !rule.Matches(state, subjects...) {
+			continue
+		}
+
+		if rule.To != state {
+			s.state = rule.To
+			s.stateSetExplicitly = true
+			s.output += fmt.Sprintf(" (%s overridden by rule)", state)
+		}
+
+		return
+	}
+}
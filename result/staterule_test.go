@@ -0,0 +1,58 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/NETWAYS/go-check"
+)
+
+func TestOverall_ApplyStateRules(t *testing.T) {
+	o := Overall{}
+
+	disks := NewPartialResult()
+	disks.SetOutput("Disks")
+
+	boot := NewPartialResult()
+	boot.SetOutput("Disk /boot is 95% used")
+	boot.SetState(check.Warning)
+	boot.AddPerfdata(&check.Perfdata{Label: "/boot", Value: 95})
+
+	root := NewPartialResult()
+	root.SetOutput("Disk / is 10% used")
+	root.SetState(check.OK)
+
+	disks.AddSubcheck(boot)
+	disks.AddSubcheck(root)
+
+	o.AddSubcheck(disks)
+	o.Add(check.Unknown, "Could not read /data")
+
+	mapBoot, _ := check.ParseStateRule("^/boot$=warning->ok")
+	unknownAs, _ := check.ParseStateRule("unknown->critical")
+
+	o.ApplyStateRules(mapBoot, unknownAs)
+
+	if boot.GetStatus() != check.OK {
+		t.Fatalf("expected %v, got %v", check.OK, boot.GetStatus())
+	}
+
+	if disks.GetStatus() != check.OK {
+		t.Fatalf("expected derived state %v, got %v", check.OK, disks.GetStatus())
+	}
+
+	if o.GetStatus() != check.Critical {
+		t.Fatalf("expected %v, got %v", check.Critical, o.GetStatus())
+	}
+
+	expected := `Could not read /data (UNKNOWN overridden by rule)
+\_ [OK] Disks
+    \_ [OK] Disk /boot is 95% used (WARNING overridden by rule)
+    \_ [OK] Disk / is 10% used
+\_ [CRITICAL] Could not read /data (UNKNOWN overridden by rule)
+|/boot=95
+`
+
+	if o.GetOutput() != expected {
+		t.Fatalf("expected %q, got %q", expected, o.GetOutput())
+	}
+}
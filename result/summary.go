@@ -0,0 +1,139 @@
+package result
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/NETWAYS/go-check"
+)
+
+// SummaryMode defines how the summary (first line of output) of a non-OK Overall is built
+type SummaryMode int
+
+const (
+	// SummaryWorst shows the output of the worst PartialResult (default)
+	SummaryWorst SummaryMode = iota
+	// SummaryAllFailing lists the outputs of all failing PartialResults, worst first,
+	// e.g. "/boot is full, /var is full and 2 more"
+	SummaryAllFailing
+	// SummaryGrouped lists the outputs of all failing PartialResults grouped by state,
+	// e.g. "CRITICAL: /boot is full, /var is full; WARNING: /tmp is almost full"
+	SummaryGrouped
+	// SummaryCounts shows the number of PartialResults by state, e.g. "states: critical=1 warning=2 ok=3"
+	SummaryCounts
+)
+
+// failedOutput is the output of a failing PartialResult for the summary
+type failedOutput struct {
+	state  check.Status
+	output string
+}
+
+// SetSummaryMode sets how the summary of a non-OK Overall is built, see SummaryWorst and following
+func (o *Overall) SetSummaryMode(mode SummaryMode) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.summaryMode = mode
+}
+
+// SetSummaryLimit limits the number of outputs listed by SummaryAllFailing and SummaryGrouped,
+// the remaining ones are summarized as "and N more". 0 means no limit.
+func (o *Overall) SetSummaryLimit(limit int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.summaryLimit = limit
+}
+
+// getFailingSummary returns the summary for SummaryAllFailing and SummaryGrouped,
+// empty if no failing PartialResult has an output
+func (o *Overall) getFailingSummary() string {
+	var failed []failedOutput
+
+	for _, sc := range activeResults(o.partialResults) {
+		failed = append(failed, sc.getFailedOutputs()...)
+	}
+
+	if len(failed) == 0 {
+		return ""
+	}
+
+	// Worst first, the order of the PartialResults is kept within a state
+	sort.SliceStable(failed, func(i, j int) bool {
+		return check.Compare(failed[i].state, failed[j].state) < 0
+	})
+
+	more := 0
+
+	if o.summaryLimit > 0 && len(failed) > o.summaryLimit {
+		more = len(failed) - o.summaryLimit
+		failed = failed[:o.summaryLimit]
+	}
+
+	var summary string
+
+	if o.summaryMode == SummaryGrouped {
+		var groups []string
+
+		for i := 0; i < len(failed); {
+			state := failed[i].state
+			outputs := []string{}
+
+			for ; i < len(failed) && failed[i].state == state; i++ {
+				outputs = append(outputs, failed[i].output)
+			}
+
+			groups = append(groups, state.String()+": "+strings.Join(outputs, ", "))
+		}
+
+		summary = strings.Join(groups, "; ")
+	} else {
+		outputs := make([]string, len(failed))
+
+		for i := range failed {
+			outputs[i] = failed[i].output
+		}
+
+		summary = strings.Join(outputs, ", ")
+	}
+
+	if more > 0 {
+		summary += fmt.Sprintf(" and %d more", more)
+	}
+
+	return summary
+}
+
+// getFailedOutputs returns the outputs of the deepest failing PartialResults below and including this one
+func (s *PartialResult) getFailedOutputs() []failedOutput {
+	state := s.GetStatus()
+	if state == check.OK {
+		return nil
+	}
+
+	s.mu.RLock()
+	children := activeResults(s.partialResults)
+	_, isSummarizer := s.aggregation.(summarizer)
+	s.mu.RUnlock()
+
+	var failed []failedOutput
+
+	// The aggregation (e.g. a quorum) failed as a whole, not a single PartialResult
+	if !isSummarizer {
+		for _, sc := range children {
+			failed = append(failed, sc.getFailedOutputs()...)
+		}
+	}
+
+	if len(failed) > 0 {
+		return failed
+	}
+
+	if output := s.getOutputLine(); output != "" {
+		return []failedOutput{{state: state, output: output}}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/NETWAYS/go-check"
+)
+
+func TestOverall_SetSummaryMode(t *testing.T) {
+	testcases := map[string]struct {
+		mode     SummaryMode
+		limit    int
+		expected string
+	}{
+		"worst": {
+			mode:     SummaryWorst,
+			expected: "/boot is full",
+		},
+		"all-failing": {
+			mode:     SummaryAllFailing,
+			expected: "/boot is full, /var is full, /mnt is not mounted, /tmp is almost full",
+		},
+		"all-failing-limit": {
+			mode:     SummaryAllFailing,
+			limit:    2,
+			expected: "/boot is full, /var is full and 2 more",
+		},
+		"grouped": {
+			mode:     SummaryGrouped,
+			expected: "CRITICAL: /boot is full, /var is full; UNKNOWN: /mnt is not mounted; WARNING: /tmp is almost full",
+		},
+		"grouped-limit": {
+			mode:     SummaryGrouped,
+			limit:    3,
+			expected: "CRITICAL: /boot is full, /var is full; UNKNOWN: /mnt is not mounted and 1 more",
+		},
+		"counts": {
+			mode:     SummaryCounts,
+			expected: "states: critical=2 unknown=1",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			o := newTestOverall(
+				testResult{output: "Disks", subchecks: []testResult{
+					{state: check.Warning, output: "/tmp is almost full"},
+					{state: check.Critical, output: "/boot is full"},
+					{state: check.OK, output: "/ is fine"},
+				}},
+				testResult{state: check.Unknown, output: "/mnt is not mounted"},
+				testResult{state: check.Critical, output: "/var is full"},
+			)
+			o.SetSummaryMode(tc.mode)
+			o.SetSummaryLimit(tc.limit)
+
+			if o.getSummary() != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, o.getSummary())
+			}
+		})
+	}
+}
+
+func TestOverall_SetSummaryMode_OK(t *testing.T) {
+	o := Overall{}
+	o.Add(check.OK, "Everything is fine")
+	o.SetSummaryMode(SummaryAllFailing)
+
+	if o.getSummary() != "states: ok=1" {
+		t.Fatalf("expected %q, got %q", "states: ok=1", o.getSummary())
+	}
+}
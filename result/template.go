@@ -0,0 +1,108 @@
+package result
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/NETWAYS/go-check"
+)
+
+// SummaryData is passed to the summary templates, see Overall.SetSummaryTemplate
+type SummaryData struct {
+	// State of the Overall
+	State check.Status
+	// Number of PartialResults of the Overall, in total and by state
+	Total    int
+	OK       int
+	Warning  int
+	Critical int
+	Unknown  int
+	// Names of the PartialResults by state, the output is used for PartialResults without a name
+	OKNames       []string
+	WarningNames  []string
+	CriticalNames []string
+	UnknownNames  []string
+	// Perfdata of all PartialResults, including nested ones
+	Perfdata check.PerfdataList
+}
+
+// SetSummaryTemplate sets a Go text/template for the summary (first line of output) of the given states,
+// or of all states if none are given. See SummaryData for the available fields and
+// check.SummaryTemplateFuncs for the functions, e.g.:
+//
+//	{{.Critical}} of {{.Total}} disks critical: {{join .CriticalNames}}
+//
+// If the template fails to execute, the default summary is used.
+func (o *Overall) SetSummaryTemplate(text string, states ...check.Status) error {
+	tmpl, err := template.New("summary").Funcs(check.SummaryTemplateFuncs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("could not parse summary template: %w", err)
+	}
+
+	if len(states) == 0 {
+		states = []check.Status{check.OK, check.Warning, check.Critical, check.Unknown}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.summaryTemplates == nil {
+		o.summaryTemplates = map[check.Status]*template.Template{}
+	}
+
+	for _, state := range states {
+		o.summaryTemplates[state] = tmpl
+	}
+
+	return nil
+}
+
+// getTemplateSummary returns the summary of the template for the state, false if there is none or it failed
+func (o *Overall) getTemplateSummary(state check.Status) (string, bool) {
+	tmpl, ok := o.summaryTemplates[state]
+	if !ok {
+		return "", false
+	}
+
+	var sb strings.Builder
+
+	if err := tmpl.Execute(&sb, o.getSummaryData(state)); err != nil {
+		return "", false
+	}
+
+	return strings.ReplaceAll(sb.String(), check.PerfdataSeparatorSymbol, " "), true
+}
+
+// getSummaryData collects the SummaryData of all PartialResults that are not ignored
+func (o *Overall) getSummaryData(state check.Status) SummaryData {
+	data := SummaryData{State: state}
+
+	for _, sc := range activeResults(o.partialResults) {
+		name := sc.GetName()
+		if name == "" {
+			name = sc.getOutputLine()
+		}
+
+		data.Total++
+
+		switch sc.GetStatus() {
+		case check.OK:
+			data.OK++
+			data.OKNames = append(data.OKNames, name)
+		case check.Warning:
+			data.Warning++
+			data.WarningNames = append(data.WarningNames, name)
+		case check.Critical:
+			data.Critical++
+			data.CriticalNames = append(data.CriticalNames, name)
+		default:
+			data.Unknown++
+			data.UnknownNames = append(data.UnknownNames, name)
+		}
+
+		data.Perfdata = append(data.Perfdata, sc.getPerfdataList(perfdataContext{})...)
+	}
+
+	return data
+}
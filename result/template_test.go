@@ -0,0 +1,58 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/NETWAYS/go-check"
+)
+
+func TestOverall_SetSummaryTemplate(t *testing.T) {
+	o := Overall{}
+
+	for _, disk := range []struct {
+		name  string
+		state check.Status
+	}{
+		{"/", check.OK},
+		{"/boot", check.Critical},
+		{"/var", check.Critical},
+	} {
+		sc := NewPartialResult()
+		sc.SetName(disk.name)
+		sc.SetState(disk.state)
+		sc.AddPerfdata(&check.Perfdata{Label: disk.name, Value: 1})
+
+		o.AddSubcheck(sc)
+	}
+
+	o.Add(check.OK, "Mounts are readable")
+
+	err := o.SetSummaryTemplate("{{.Critical}} of {{.Total}} disks critical: {{join .CriticalNames}}", check.Critical)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "2 of 4 disks critical: /boot, /var"
+	if o.getSummary() != expected {
+		t.Fatalf("expected %q, got %q", expected, o.getSummary())
+	}
+
+	_ = o.SetSummaryTemplate(`{{.State}} {{join .OKNames " + "}} {{range .Perfdata}}{{.Label}} {{end}}`)
+
+	expected = "CRITICAL / + Mounts are readable / /boot /var "
+	if o.getSummary() != expected {
+		t.Fatalf("expected %q, got %q", expected, o.getSummary())
+	}
+
+	// Falls back to the default summary
+	_ = o.SetSummaryTemplate("{{.Missing}}")
+
+	expected = "states: critical=2 ok=2"
+	if o.getSummary() != expected {
+		t.Fatalf("expected %q, got %q", expected, o.getSummary())
+	}
+
+	if o.SetSummaryTemplate("{{.Critical") == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
@@ -0,0 +1,123 @@
+package result
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/NETWAYS/go-check"
+)
+
+// PathSeparator separates the names of nested PartialResults in a path for Find
+const PathSeparator = "/"
+
+// ErrSkipChildren can be returned by a WalkFunc to skip the PartialResults below the current one
+var ErrSkipChildren = errors.New("skip children")
+
+// WalkFunc is called by Walk for every PartialResult. path contains the names of all
+// parents and the PartialResult itself, empty for PartialResults without a name.
+//
+// If ErrSkipChildren is returned, the PartialResults below are skipped,
+// any other error stops the walk and is returned by Walk.
+type WalkFunc func(path []string, sc *PartialResult) error
+
+// Children returns the PartialResults of the Overall
+func (o *Overall) Children() []*PartialResult {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return append([]*PartialResult(nil), o.partialResults...)
+}
+
+// Children returns the PartialResults of the PartialResult
+func (s *PartialResult) Children() []*PartialResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]*PartialResult(nil), s.partialResults...)
+}
+
+// Perfdata returns the perfdata of the PartialResult, without the perfdata of its PartialResults
+func (s *PartialResult) Perfdata() check.PerfdataList {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append(check.PerfdataList(nil), s.perfdata...)
+}
+
+// Find returns the first PartialResult matching the path of names, e.g. "cluster/node1/disk".
+// Returns nil if there is no matching PartialResult. See Lookup for names containing the PathSeparator.
+func (o *Overall) Find(path string) *PartialResult {
+	return o.Lookup(strings.Split(path, PathSeparator)...)
+}
+
+// Lookup returns the first PartialResult matching the names, each name identifies a level of the tree.
+// Returns nil if there is no matching PartialResult.
+func (o *Overall) Lookup(names ...string) *PartialResult {
+	return lookup(o.Children(), names)
+}
+
+// Find returns the first PartialResult below this one matching the path of names, see Overall.Find
+func (s *PartialResult) Find(path string) *PartialResult {
+	return s.Lookup(strings.Split(path, PathSeparator)...)
+}
+
+// Lookup returns the first PartialResult below this one matching the names, see Overall.Lookup
+func (s *PartialResult) Lookup(names ...string) *PartialResult {
+	return lookup(s.Children(), names)
+}
+
+func lookup(partialResults []*PartialResult, names []string) *PartialResult {
+	if len(names) == 0 {
+		return nil
+	}
+
+	for _, sc := range partialResults {
+		if sc.GetName() != names[0] {
+			continue
+		}
+
+		if len(names) == 1 {
+			return sc
+		}
+
+		if found := sc.Lookup(names[1:]...); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// Walk calls fn for every PartialResult of the Overall in depth-first order,
+// a PartialResult is visited before the PartialResults below it.
+func (o *Overall) Walk(fn WalkFunc) error {
+	return walk(o.Children(), nil, fn)
+}
+
+// Walk calls fn for every PartialResult below this one, see Overall.Walk
+func (s *PartialResult) Walk(fn WalkFunc) error {
+	return walk(s.Children(), nil, fn)
+}
+
+func walk(partialResults []*PartialResult, parents []string, fn WalkFunc) error {
+	for _, sc := range partialResults {
+		// Copy the path, so fn can keep it
+		path := append(append(make([]string, 0, len(parents)+1), parents...), sc.GetName())
+
+		err := fn(path, sc)
+
+		if errors.Is(err, ErrSkipChildren) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := walk(sc.Children(), path, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
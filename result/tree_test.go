@@ -0,0 +1,97 @@
+package result
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/NETWAYS/go-check"
+)
+
+func TestOverall_Find(t *testing.T) {
+	o := newTestOverall(
+		testResult{name: "cluster", subchecks: []testResult{
+			{name: "node1", subchecks: []testResult{
+				{name: "disk", state: check.Unknown, output: "node1 disk", perfdata: []*check.Perfdata{{Label: "usage", Value: 42}}},
+			}},
+			{name: "node2", subchecks: []testResult{
+				{name: "disk", state: check.Unknown, output: "node2 disk", perfdata: []*check.Perfdata{{Label: "usage", Value: 42}}},
+			}},
+		}},
+		testResult{name: "/boot", state: check.Unknown},
+	)
+
+	disk := o.Find("cluster/node2/disk")
+	if disk == nil {
+		t.Fatalf("expected PartialResult, got nil")
+	}
+
+	if disk.String() != "[UNKNOWN] node2 disk" {
+		t.Fatalf("expected %v, got %v", "[UNKNOWN] node2 disk", disk.String())
+	}
+
+	if len(disk.Perfdata()) != 1 || disk.Perfdata()[0].Label != "usage" {
+		t.Fatalf("expected perfdata usage, got %v", disk.Perfdata())
+	}
+
+	if o.Find("cluster").Find("node1/disk") == nil {
+		t.Fatalf("expected PartialResult, got nil")
+	}
+
+	if o.Lookup("/boot") == nil {
+		t.Fatalf("expected PartialResult, got nil")
+	}
+
+	for _, path := range []string{"", "cluster/node3", "cluster/node1/disk/foo", "/boot"} {
+		if o.Find(path) != nil {
+			t.Fatalf("expected nil for %q", path)
+		}
+	}
+}
+
+func TestOverall_Walk(t *testing.T) {
+	o := newTestOverall(
+		testResult{name: "cluster", subchecks: []testResult{
+			{name: "node1", subchecks: []testResult{
+				{name: "disk", state: check.Unknown, output: "node1 disk", perfdata: []*check.Perfdata{{Label: "usage", Value: 42}}},
+			}},
+			{name: "node2", subchecks: []testResult{
+				{name: "disk", state: check.Unknown, output: "node2 disk", perfdata: []*check.Perfdata{{Label: "usage", Value: 42}}},
+			}},
+		}},
+		testResult{name: "/boot", state: check.Unknown},
+	)
+
+	var paths []string
+
+	err := o.Walk(func(path []string, sc *PartialResult) error {
+		paths = append(paths, strings.Join(path, PathSeparator))
+
+		if sc.GetName() == "node1" {
+			return ErrSkipChildren
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "cluster,cluster/node1,cluster/node2,cluster/node2/disk,/boot"
+	if strings.Join(paths, ",") != expected {
+		t.Fatalf("expected %v, got %v", expected, strings.Join(paths, ","))
+	}
+
+	errStop := errors.New("stop")
+
+	err = o.Walk(func(_ []string, _ *PartialResult) error {
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected %v, got %v", errStop, err)
+	}
+
+	if len(o.Children()) != 2 || len(o.Find("cluster").Children()) != 2 {
+		t.Fatalf("expected 2 children")
+	}
+}
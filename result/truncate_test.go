@@ -0,0 +1,104 @@
+package result
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NETWAYS/go-check"
+)
+
+func TestOverall_SetMaxOutputLength(t *testing.T) {
+	disks := []testResult{
+		{state: check.OK, output: "/ is fine"},
+		{state: check.OK, output: "/var is fine"},
+		{state: check.OK, output: "/tmp is fine"},
+	}
+
+	results := []testResult{
+		{output: "node1", subchecks: disks},
+		{output: "node2", subchecks: disks},
+		{state: check.Critical, output: "/boot is full"},
+		{state: check.OK, output: "perfdata", perfdata: []*check.Perfdata{{Label: "usage", Value: 42}}},
+	}
+
+	testcases := map[string]struct {
+		maxLength int
+		expected  string
+	}{
+		"collapse-ok": {
+			maxLength: 140,
+			expected: `/boot is full
+\_ [OK] node1
+\_ [OK] node2
+\_ [CRITICAL] /boot is full
+\_ [OK] perfdata
+(output truncated)
+|usage=42
+`,
+		},
+		"failed-only": {
+			maxLength: 80,
+			expected: `/boot is full
+\_ [CRITICAL] /boot is full
+(output truncated)
+|usage=42
+`,
+		},
+		"truncated": {
+			maxLength: 45,
+			expected: `/boot is full
+(output truncated)
+|usage=42
+`,
+		},
+		"minimal": {
+			maxLength: 3,
+			expected:  "(\n",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			o := newTestOverall(results...)
+			o.SetMaxOutputLength(tc.maxLength)
+
+			output := o.GetOutput()
+
+			if output != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, output)
+			}
+
+			if len(output) > tc.maxLength {
+				t.Fatalf("expected at most %d bytes, got %d", tc.maxLength, len(output))
+			}
+		})
+	}
+
+	o := newTestOverall(results...)
+	o.SetMaxOutputLength(1000)
+
+	if strings.Contains(o.GetOutput(), check.TruncatedMarker) {
+		t.Fatalf("expected output not to be truncated, got %q", o.GetOutput())
+	}
+}
+
+func TestOverall_SetMaxOutputLength_Global(t *testing.T) {
+	check.MaxOutputLength = 30
+
+	defer func() {
+		check.MaxOutputLength = 0
+	}()
+
+	o := newTestOverall(testResult{state: check.Critical, output: "/boot is full"}, testResult{state: check.OK, output: "/ is fine"})
+
+	if !strings.Contains(o.GetOutput(), check.TruncatedMarker) {
+		t.Fatalf("expected output to be truncated, got %q", o.GetOutput())
+	}
+
+	// An explicit limit overrides the global one, 0 disables it
+	o.SetMaxOutputLength(0)
+
+	if strings.Contains(o.GetOutput(), check.TruncatedMarker) {
+		t.Fatalf("expected output not to be truncated, got %q", o.GetOutput())
+	}
+}
@@ -0,0 +1,156 @@
+package check
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// StateRule replaces the state of matching results, e.g. to demote a known-noisy condition
+type StateRule struct {
+	// Pattern is matched against the output and the perfdata labels of a result, nil matches everything
+	Pattern *regexp.Regexp
+	// From is the state to replace, ignored if AnyState is set
+	From     Status
+	AnyState bool
+	// To is the new state
+	To Status
+}
+
+// ParseStateRule parses a rule in the format "[pattern=]from->to", where pattern is a regular expression
+// and from is either a state or "*" for any state, e.g.:
+//
+//	disk:/boot=warning->ok
+//	unknown->critical
+//	timeout=*->warning
+func ParseStateRule(spec string) (*StateRule, error) {
+	left, to, found := cut(spec, "->")
+	if !found {
+		return nil, fmt.Errorf("invalid state rule, expected [pattern=]from->to: %s", spec)
+	}
+
+	rule := &StateRule{}
+
+	pattern, from, found := cut(left, "=")
+	if !found {
+		pattern, from = "", left
+	}
+
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern in state rule %s: %w", spec, err)
+		}
+
+		rule.Pattern = re
+	}
+
+	var err error
+
+	if strings.TrimSpace(from) == "*" {
+		rule.AnyState = true
+	} else if rule.From, err = NewStatusFromString(strings.TrimSpace(from)); err != nil {
+		return nil, fmt.Errorf("invalid state rule %s: %w", spec, err)
+	}
+
+	if rule.To, err = NewStatusFromString(strings.TrimSpace(to)); err != nil {
+		return nil, fmt.Errorf("invalid state rule %s: %w", spec, err)
+	}
+
+	return rule, nil
+}
+
+// cut slices s around the last instance of sep, since the pattern itself may contain the separator
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+
+	return s, "", false
+}
+
+// Matches returns true if the rule applies to the given state and any of the subjects (e.g. output and labels)
+func (r *StateRule) Matches(state Status, subjects ...string) bool {
+	if !r.AnyState && r.From != state {
+		return false
+	}
+
+	if r.Pattern == nil {
+		return true
+	}
+
+	for _, subject := range subjects {
+		if r.Pattern.MatchString(subject) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns the rule in the format of ParseStateRule
+func (r *StateRule) String() string {
+	var sb strings.Builder
+
+	if r.Pattern != nil {
+		sb.WriteString(r.Pattern.String() + "=")
+	}
+
+	if r.AnyState {
+		sb.WriteString("*")
+	} else {
+		sb.WriteString(strings.ToLower(r.From.String()))
+	}
+
+	sb.WriteString("->" + strings.ToLower(r.To.String()))
+
+	return sb.String()
+}
+
+// StateRuleOptions contains the rules of the flags registered by AddStateRuleFlags
+type StateRuleOptions struct {
+	Rules []*StateRule
+}
+
+// AddStateRuleFlags registers the --map-state and --unknown-as flags.
+//
+// --map-state can be passed multiple times with a rule for ParseStateRule.
+// --unknown-as adds a final rule replacing every remaining Unknown state.
+// The rules are applied with result.Overall.ApplyStateRules.
+func (c *Config) AddStateRuleFlags() *StateRuleOptions {
+	o := &StateRuleOptions{}
+
+	var (
+		mappings  []string
+		unknownAs string
+	)
+
+	c.FlagSet.StringArrayVar(&mappings, "map-state", nil, "Replace the state of matching results, format: [pattern=]from->to")
+	c.FlagSet.StringVar(&unknownAs, "unknown-as", "", "Replace the Unknown state of all results with the given state")
+
+	c.validators = append(c.validators, func() error {
+		o.Rules = nil
+
+		for _, spec := range mappings {
+			rule, err := ParseStateRule(spec)
+			if err != nil {
+				return err
+			}
+
+			o.Rules = append(o.Rules, rule)
+		}
+
+		if unknownAs != "" {
+			to, err := NewStatusFromString(unknownAs)
+			if err != nil {
+				return fmt.Errorf("invalid value for --unknown-as: %w", err)
+			}
+
+			o.Rules = append(o.Rules, &StateRule{From: Unknown, To: to})
+		}
+
+		return nil
+	})
+
+	return o
+}
@@ -0,0 +1,106 @@
+package check
+
+import (
+	"testing"
+)
+
+func TestParseStateRule(t *testing.T) {
+	testcases := map[string]struct {
+		spec     string
+		expected string
+	}{
+		"with-pattern": {
+			spec:     "disk:/boot=warning->ok",
+			expected: "disk:/boot=warning->ok",
+		},
+		"without-pattern": {
+			spec:     "UNKNOWN->critical",
+			expected: "unknown->critical",
+		},
+		"any-state": {
+			spec:     "timeout=*->warning",
+			expected: "timeout=*->warning",
+		},
+		"pattern-with-separator": {
+			spec:     "a=b=critical->warning",
+			expected: "a=b=critical->warning",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			rule, err := ParseStateRule(tc.spec)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if rule.String() != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, rule.String())
+			}
+		})
+	}
+}
+
+func TestParseStateRule_WithErr(t *testing.T) {
+	testcases := []string{
+		"",
+		"warning",
+		"disk=warning->foo",
+		"disk=foo->ok",
+		"[=warning->ok",
+	}
+
+	for _, spec := range testcases {
+		if _, err := ParseStateRule(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestStateRule_Matches(t *testing.T) {
+	rule, _ := ParseStateRule("^/boot$=warning->ok")
+
+	if !rule.Matches(Warning, "Disk /boot is full", "/boot") {
+		t.Fatalf("expected rule to match")
+	}
+
+	if rule.Matches(Warning, "Disk /boot is full") {
+		t.Fatalf("expected rule not to match the output")
+	}
+
+	if rule.Matches(Critical, "/boot") {
+		t.Fatalf("expected rule not to match another state")
+	}
+
+	anyRule := &StateRule{AnyState: true, To: OK}
+
+	if !anyRule.Matches(Critical) {
+		t.Fatalf("expected rule without pattern to match")
+	}
+}
+
+func TestConfig_AddStateRuleFlags(t *testing.T) {
+	c := newPresetConfig()
+	rules := c.AddStateRuleFlags()
+
+	c.ParseArray([]string{"--map-state", "disk:/boot=warning->ok", "--map-state", "*->warning", "--unknown-as", "critical"})
+
+	if len(rules.Rules) != 3 {
+		t.Fatalf("expected %v rules, got %v", 3, len(rules.Rules))
+	}
+
+	if rules.Rules[2].String() != "unknown->critical" {
+		t.Fatalf("expected %v, got %v", "unknown->critical", rules.Rules[2].String())
+	}
+
+	for _, args := range [][]string{{"--map-state", "warning"}, {"--unknown-as", "foo"}} {
+		c = newPresetConfig()
+		_ = c.AddStateRuleFlags()
+
+		_ = c.FlagSet.Parse(args)
+
+		if c.validate() == nil {
+			t.Fatalf("expected error for %v, got nil", args)
+		}
+	}
+}
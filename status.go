@@ -81,13 +81,74 @@ func (s Status) String() string {
 	}
 }
 
+// Precedence orders all states from the worst to the best
+type Precedence [4]Status
+
+var (
+	// PrecedenceCriticalFirst is the default: Critical, Unknown, Warning, OK
+	PrecedenceCriticalFirst = Precedence{Critical, Unknown, Warning, OK}
+	// PrecedenceUnknownFirst treats Unknown as the worst state: Unknown, Critical, Warning, OK
+	PrecedenceUnknownFirst = Precedence{Unknown, Critical, Warning, OK}
+	// PrecedenceUnknownBelowWarning downgrades Unknown below Warning: Critical, Warning, Unknown, OK
+	PrecedenceUnknownBelowWarning = Precedence{Critical, Warning, Unknown, OK}
+)
+
+// precedence is the currently used Precedence
+var precedence = PrecedenceCriticalFirst
+
+// severity maps each state to its position in the precedence, higher is worse
+var severity = severities(PrecedenceCriticalFirst)
+
+// SetPrecedence sets the order of states used by Compare, WorstState and the result package.
+// Returns an error if the precedence does not contain every state exactly once
+// or OK is not the best state.
+//
+// SetPrecedence is not concurrency-safe and should be called once at the start of a plugin.
+func SetPrecedence(p Precedence) error {
+	seen := map[Status]bool{}
+
+	for _, s := range p {
+		if s < OK || s > Unknown || seen[s] {
+			return fmt.Errorf("invalid precedence: %v", p)
+		}
+
+		seen[s] = true
+	}
+
+	// OK is assumed to be the best state, e.g. when searching for the worst state
+	if p[len(p)-1] != OK {
+		return fmt.Errorf("invalid precedence, OK must be the best state: %v", p)
+	}
+
+	precedence = p
+	severity = severities(p)
+
+	return nil
+}
+
+// GetPrecedence returns the currently used Precedence
+func GetPrecedence() Precedence {
+	return precedence
+}
+
+// severities returns the severity for each state of a Precedence, indexed by the state
+func severities(p Precedence) [4]int {
+	var result [4]int
+
+	for i, s := range p {
+		result[s] = len(p) - i
+	}
+
+	return result
+}
+
 // WorstState determines the worst state from a list of states
 //
 // This can be used to combine multiple states into a one state.
 // Order of preference: Critical, Unknown, Warning, Ok
 //
 // Note that, this precedence was decided for this package since
-// there is no specification for the preference.
+// there is no specification for the preference. It can be changed with SetPrecedence.
 // See also: https://www.monitoring-plugins.org/doc/guidelines.html#AEN74
 func WorstState(states ...Status) Status {
 	if len(states) < 1 {
@@ -97,60 +158,37 @@ func WorstState(states ...Status) Status {
 	overall := OK
 
 	for _, state := range states {
+		// Invalid states are treated as Unknown, regardless of their position
+		if state < OK || state > Unknown {
+			state = Unknown
+		}
+
 		if Compare(overall, state) > 0 {
 			overall = state
 		}
 	}
 
-	if overall < OK || overall > Unknown {
-		overall = Unknown
-	}
-
 	return overall
 }
 
-// Compare compares two Status types.
+// Compare compares two Status types according to the Precedence.
 // If the left one (a) is worse than the right one (b), the result is < 0
 //
 // # If they are equal, the result is 0
 //
 // If the right one (b) is worse than the left one (a), the result is > 0
 func Compare(a Status, b Status) int {
-	switch a {
-	case OK:
-		switch b {
-		case OK:
-			return 0
-		case Warning, Unknown, Critical:
-			return 1
-		}
-	case Warning:
-		switch b {
-		case OK:
-			return -1
-		case Warning:
-			return 0
-		case Unknown, Critical:
-			return 1
-		}
-	case Unknown:
-		switch b {
-		case OK, Warning:
-			return -1
-		case Unknown:
-			return 0
-		case Critical:
-			return 1
-		}
-	case Critical:
-		switch b {
-		case OK, Warning, Unknown:
-			return -1
-		case Critical:
-			return 0
-		}
+	// should not be possible with valid states
+	if a < OK || a > Unknown || b < OK || b > Unknown {
+		return 0
 	}
 
-	// should not be possible to land here
-	return 0
+	switch {
+	case severity[a] > severity[b]:
+		return -1
+	case severity[a] < severity[b]:
+		return 1
+	default:
+		return 0
+	}
 }
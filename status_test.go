@@ -250,6 +250,21 @@ func TestWorstState(t *testing.T) {
 			input:    []Status{},
 			expected: Unknown,
 		},
+		{
+			name:     "Invalid first with Critical",
+			input:    []Status{Status(7), Critical},
+			expected: Critical,
+		},
+		{
+			name:     "Invalid last with Critical",
+			input:    []Status{Critical, Status(7)},
+			expected: Critical,
+		},
+		{
+			name:     "Invalid with Warning",
+			input:    []Status{Status(-1), Warning},
+			expected: Unknown,
+		},
 	}
 
 	for _, tt := range tests {
@@ -276,3 +291,57 @@ func BenchmarkWorstState(b *testing.B) {
 		_ = s
 	}
 }
+
+func TestSetPrecedence(t *testing.T) {
+	defer func() {
+		_ = SetPrecedence(PrecedenceCriticalFirst)
+	}()
+
+	if err := SetPrecedence(PrecedenceUnknownFirst); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if GetPrecedence() != PrecedenceUnknownFirst {
+		t.Fatalf("expected %v, got %v", PrecedenceUnknownFirst, GetPrecedence())
+	}
+
+	if got := WorstState(OK, Critical, Unknown, Warning); got != Unknown {
+		t.Fatalf("expected %v, got %v", Unknown, got)
+	}
+
+	if got := Compare(Critical, Unknown); got != 1 {
+		t.Fatalf("expected %v, got %v", 1, got)
+	}
+
+	if err := SetPrecedence(PrecedenceUnknownBelowWarning); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got := WorstState(OK, Unknown, Warning); got != Warning {
+		t.Fatalf("expected %v, got %v", Warning, got)
+	}
+
+	if got := WorstState(OK, Unknown); got != Unknown {
+		t.Fatalf("expected %v, got %v", Unknown, got)
+	}
+}
+
+func TestSetPrecedence_Invalid(t *testing.T) {
+	testcases := map[string]Precedence{
+		"duplicate":     {Critical, Critical, Warning, OK},
+		"invalid state": {Critical, Unknown, Warning, Status(5)},
+		"ok not best":   {Critical, OK, Warning, Unknown},
+	}
+
+	for name, p := range testcases {
+		t.Run(name, func(t *testing.T) {
+			if err := SetPrecedence(p); err == nil {
+				t.Fatalf("expected error for %v", p)
+			}
+
+			if GetPrecedence() != PrecedenceCriticalFirst {
+				t.Fatalf("expected precedence to be unchanged, got %v", GetPrecedence())
+			}
+		})
+	}
+}
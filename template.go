@@ -0,0 +1,87 @@
+package check
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// SummaryTemplateFuncs are the functions available in summary templates, see result.Overall.SetSummaryTemplate
+//
+//	join  joins a list of strings, separated by ", " or the given separator
+var SummaryTemplateFuncs = template.FuncMap{
+	"join": func(items []string, sep ...string) string {
+		if len(sep) > 0 {
+			return strings.Join(items, sep[0])
+		}
+
+		return strings.Join(items, ", ")
+	},
+}
+
+// SummaryTemplateOptions contains the templates of the flags registered by AddSummaryTemplateFlags
+type SummaryTemplateOptions struct {
+	// Templates for the summary by state
+	Templates map[Status]string
+}
+
+// AddSummaryTemplateFlags registers the --summary-template flag.
+//
+// The flag can be passed multiple times in the format "state:template", e.g.
+// "critical:{{.Critical}} of {{.Total}} disks critical: {{join .CriticalNames}}".
+// A template without a state is used for all states without a specific template.
+func (c *Config) AddSummaryTemplateFlags() *SummaryTemplateOptions {
+	o := &SummaryTemplateOptions{}
+
+	var templates []string
+
+	c.FlagSet.StringArrayVar(&templates, "summary-template", nil, "Go template for the summary, format: [state:]template")
+
+	c.validators = append(c.validators, func() error {
+		o.Templates = map[Status]string{}
+
+		var generic *string
+
+		for i, spec := range templates {
+			state, text, hasState := splitSummaryTemplate(spec)
+
+			if _, err := template.New("summary").Funcs(SummaryTemplateFuncs).Parse(text); err != nil {
+				return fmt.Errorf("invalid value for --summary-template: %w", err)
+			}
+
+			if hasState {
+				o.Templates[state] = text
+			} else {
+				generic = &templates[i]
+			}
+		}
+
+		if generic != nil {
+			for _, state := range []Status{OK, Warning, Critical, Unknown} {
+				if _, ok := o.Templates[state]; !ok {
+					o.Templates[state] = *generic
+				}
+			}
+		}
+
+		return nil
+	})
+
+	return o
+}
+
+// splitSummaryTemplate splits the state from a template in the format "[state:]template".
+// The template itself may contain colons, so the prefix is only used if it is a valid state.
+func splitSummaryTemplate(spec string) (Status, string, bool) {
+	prefix, text, found := strings.Cut(spec, ":")
+	if !found {
+		return Unknown, spec, false
+	}
+
+	state, err := NewStatusFromString(prefix)
+	if err != nil {
+		return Unknown, spec, false
+	}
+
+	return state, text, true
+}
@@ -0,0 +1,37 @@
+package check
+
+import (
+	"testing"
+)
+
+func TestConfig_AddSummaryTemplateFlags(t *testing.T) {
+	c := newPresetConfig()
+	templates := c.AddSummaryTemplateFlags()
+
+	c.ParseArray([]string{
+		"--summary-template", "critical:{{.Critical}} critical: {{join .CriticalNames}}",
+		"--summary-template", "Disks: {{.Total}}",
+	})
+
+	expected := map[Status]string{
+		OK:       "Disks: {{.Total}}",
+		Warning:  "Disks: {{.Total}}",
+		Critical: "{{.Critical}} critical: {{join .CriticalNames}}",
+		Unknown:  "Disks: {{.Total}}",
+	}
+
+	for state, text := range expected {
+		if templates.Templates[state] != text {
+			t.Fatalf("expected %q for %v, got %q", text, state, templates.Templates[state])
+		}
+	}
+
+	c = newPresetConfig()
+	_ = c.AddSummaryTemplateFlags()
+
+	_ = c.FlagSet.Parse([]string{"--summary-template", "warning:{{.Warning"})
+
+	if c.validate() == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
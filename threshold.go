@@ -85,7 +85,12 @@ func ParseThreshold(spec string) (*Threshold, error) {
 
 // String returns the plain representation of the Threshold
 func (t Threshold) String() string {
-	s := BoundaryToString(t.Upper)
+	return t.format(FormatFloat)
+}
+
+// format returns the string representation with the boundaries formatted by formatFloat
+func (t Threshold) format(formatFloat func(float64) string) string {
+	s := boundaryToString(t.Upper, formatFloat)
 
 	// remove upper ~, which is the default
 	if s == NegativeInfinitySymbol {
@@ -93,7 +98,7 @@ func (t Threshold) String() string {
 	}
 
 	if t.Lower != 0 {
-		s = BoundaryToString(t.Lower) + RangeSeparatorSymbol + s
+		s = boundaryToString(t.Lower, formatFloat) + RangeSeparatorSymbol + s
 	}
 
 	if t.Inside {
@@ -114,7 +119,12 @@ func (t Threshold) DoesViolate(value float64) bool {
 
 // BoundaryToString returns the string representation of a Threshold boundary.
 func BoundaryToString(value float64) string {
-	s := FormatFloat(value)
+	return boundaryToString(value, FormatFloat)
+}
+
+// boundaryToString returns the string representation of a Threshold boundary formatted by formatFloat
+func boundaryToString(value float64, formatFloat func(float64) string) string {
+	s := formatFloat(value)
 
 	// In the threshold context, the sign derives from lower and upper bound, we only need the ~ notation
 	if s == "+Inf" || s == "-Inf" {
@@ -128,3 +138,11 @@ func BoundaryToString(value float64) string {
 func FormatFloat(value float64) string {
 	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.3f", value), "0"), ".") // remove trailing 0 and trailing dot
 }
+
+// formatExactFloat returns a string representation of floats with up to 15 significant digits,
+// which avoids artifacts of calculations like 1.1 * 1000, instead of three decimals like FormatFloat
+func formatExactFloat(value float64) string {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'g', 15, 64), 64)
+
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
@@ -0,0 +1,112 @@
+package check
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// tlsVersions maps the values of --min-tls-version to the versions of crypto/tls
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSOptions contains the values of the flags registered by AddTLSFlags
+type TLSOptions struct {
+	// Skip the verification of the server certificate
+	Insecure bool
+	// CA certificates to verify the server certificate (PEM)
+	CAFile string
+	// Client certificate and key for authentication (PEM)
+	CertFile string
+	KeyFile  string
+	// Minimum TLS version, e.g. "1.2"
+	MinVersion string
+	// Server name for SNI and the certificate verification, defaults to the connected host
+	ServerName string
+	// Config is the tls.Config built from the options when the arguments are parsed
+	Config *tls.Config
+}
+
+// AddTLSFlags registers the --insecure, --ca-file, --cert-file, --key-file, --min-tls-version and --sni flags.
+//
+// The resulting tls.Config is built when the arguments are parsed and stored in TLSOptions.Config,
+// so invalid values or unreadable files cause an exit with the UNKNOWN state before the check is executed.
+func (c *Config) AddTLSFlags() *TLSOptions {
+	o := &TLSOptions{}
+
+	c.FlagSet.BoolVar(&o.Insecure, "insecure", false, "Skip the verification of the server's TLS certificate")
+	c.FlagSet.StringVar(&o.CAFile, "ca-file", "", "Path to the CA certificates to verify the server's TLS certificate")
+	c.FlagSet.StringVar(&o.CertFile, "cert-file", "", "Path to the client certificate for TLS authentication")
+	c.FlagSet.StringVar(&o.KeyFile, "key-file", "", "Path to the client key for TLS authentication")
+	c.FlagSet.StringVar(&o.MinVersion, "min-tls-version", "1.2", "Minimum TLS version (1.0, 1.1, 1.2, 1.3)")
+	c.FlagSet.StringVar(&o.ServerName, "sni", "", "Server name for SNI and the certificate verification")
+
+	c.validators = append(c.validators, func() error {
+		config, err := o.TLSConfig()
+		if err != nil {
+			return err
+		}
+
+		o.Config = config
+
+		return nil
+	})
+
+	return o
+}
+
+// TLSConfig returns a tls.Config based on the options.
+// The CA and client certificates are read from disk on every call, after parsing the arguments
+// the Config built by AddTLSFlags can be used instead.
+func (o *TLSOptions) TLSConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		// The user explicitly asked to skip the verification
+		InsecureSkipVerify: o.Insecure, //nolint: gosec
+		ServerName:         o.ServerName,
+	}
+
+	if o.MinVersion != "" {
+		version, ok := tlsVersions[o.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("invalid minimum TLS version: %s", o.MinVersion)
+		}
+
+		config.MinVersion = version
+	}
+
+	if o.CAFile != "" {
+		data, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no valid certificates found in CA file: %s", o.CAFile)
+		}
+
+		config.RootCAs = pool
+	}
+
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return nil, errors.New("--cert-file and --key-file must be used together")
+	}
+
+	if o.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load client certificate: %w", err)
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
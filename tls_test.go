@@ -0,0 +1,123 @@
+package check
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCertificate generates a self-signed certificate and key in dir
+func writeTestCertificate(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	_ = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	_ = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600)
+
+	return certFile, keyFile
+}
+
+func TestConfig_AddTLSFlags(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t, t.TempDir())
+
+	c := newPresetConfig()
+	options := c.AddTLSFlags()
+
+	c.ParseArray([]string{
+		"--ca-file", certFile,
+		"--cert-file", certFile,
+		"--key-file", keyFile,
+		"--min-tls-version", "1.3",
+		"--sni", "example.com",
+	})
+
+	config := options.Config
+	if config == nil {
+		t.Fatalf("expected tls.Config, got nil")
+	}
+
+	if config.MinVersion != tls.VersionTLS13 {
+		t.Fatalf("expected %v, got %v", tls.VersionTLS13, config.MinVersion)
+	}
+
+	if config.ServerName != "example.com" {
+		t.Fatalf("expected %v, got %v", "example.com", config.ServerName)
+	}
+
+	if config.RootCAs == nil {
+		t.Fatalf("expected RootCAs, got nil")
+	}
+
+	if len(config.Certificates) != 1 {
+		t.Fatalf("expected 1 client certificate, got %d", len(config.Certificates))
+	}
+}
+
+func TestTLSOptions_TLSConfig_WithErr(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir)
+
+	testcases := map[string]TLSOptions{
+		"invalid-version": {
+			MinVersion: "1.4",
+		},
+		"missing-ca-file": {
+			CAFile: filepath.Join(dir, "missing.pem"),
+		},
+		"invalid-ca-file": {
+			CAFile: keyFile,
+		},
+		"missing-key-file": {
+			CertFile: certFile,
+		},
+		"invalid-key-pair": {
+			CertFile: certFile,
+			KeyFile:  certFile,
+		},
+	}
+
+	for name, options := range testcases {
+		t.Run(name, func(t *testing.T) {
+			_, err := options.TLSConfig()
+			if err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package check
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// TruncatedMarker is appended to the text output, when it was truncated to the maximum output length
+const TruncatedMarker = "(output truncated)"
+
+// MaxOutputLength limits the length of the output of ExitWithPerfdata in bytes, 0 means no limit.
+// It is also the default for result.Overall, see Overall.SetMaxOutputLength.
+//
+// Nagios truncates the plugin output at 4KB (8KB since 4.x), which can cut off the perfdata.
+var MaxOutputLength = 0
+
+// TruncateOutput limits text and perfdata to maxLength bytes in total, while keeping the summary
+// (first line of text) and the perfdata as long as possible.
+//
+// The long output is truncated by whole lines and marked with the TruncatedMarker.
+// If the perfdata alone does not fit, it is truncated by whole points.
+func TruncateOutput(text, perfdata string, maxLength int) (string, string) {
+	if maxLength <= 0 || len(text)+len(perfdata) <= maxLength {
+		return text, perfdata
+	}
+
+	summary, _, _ := strings.Cut(text, "\n")
+
+	// Reserve space for the summary before the perfdata
+	perfdataLength := maxLength - min(len(summary), maxLength/2) - len(TruncatedMarker) - 1
+	if len(perfdata) > perfdataLength {
+		perfdata = truncatePerfdata(perfdata, perfdataLength)
+	}
+
+	return truncateText(text, maxLength-len(perfdata)), perfdata
+}
+
+// truncateText truncates the text by whole lines and appends the TruncatedMarker on a separate line.
+// If the first line does not fit, it is truncated itself.
+func truncateText(text string, maxLength int) string {
+	if len(text) <= maxLength {
+		return text
+	}
+
+	// Kept lines end with a line break, since the text did not fit as a whole
+	budget := maxLength - len(TruncatedMarker)
+	if budget <= 1 {
+		return truncateString(TruncatedMarker, maxLength)
+	}
+
+	var sb strings.Builder
+
+	lines := strings.SplitAfter(text, "\n")
+
+	for i, line := range lines {
+		if sb.Len()+len(line) > budget {
+			if i == 0 {
+				// The summary itself is too long, keep as much as possible on the same line
+				return truncateString(strings.TrimSuffix(line, "\n"), budget-1) + " " + TruncatedMarker
+			}
+
+			break
+		}
+
+		sb.WriteString(line)
+	}
+
+	return sb.String() + TruncatedMarker
+}
+
+// truncatePerfdata truncates the perfdata by whole points
+func truncatePerfdata(perfdata string, maxLength int) string {
+	if len(perfdata) <= maxLength {
+		return perfdata
+	}
+
+	end := 0
+	quoted := false
+
+	// Split at spaces between points, labels may contain quoted spaces
+	for i := 0; i <= maxLength && i < len(perfdata); i++ {
+		switch perfdata[i] {
+		case '\'':
+			quoted = !quoted
+		case ' ':
+			if !quoted {
+				end = i
+			}
+		}
+	}
+
+	return perfdata[:end]
+}
+
+// truncateString truncates s to maxLength bytes without splitting a multibyte character
+func truncateString(s string, maxLength int) string {
+	if len(s) <= maxLength {
+		return s
+	}
+
+	for maxLength > 0 && !utf8.RuneStart(s[maxLength]) {
+		maxLength--
+	}
+
+	return s[:maxLength]
+}
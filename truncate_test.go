@@ -0,0 +1,76 @@
+package check
+
+import (
+	"testing"
+)
+
+func TestTruncateOutput(t *testing.T) {
+	testcases := map[string]struct {
+		text             string
+		perfdata         string
+		maxLength        int
+		expectedText     string
+		expectedPerfdata string
+	}{
+		"no-limit": {
+			text:             "summary\nline 1\nline 2",
+			perfdata:         "a=1 b=2",
+			maxLength:        0,
+			expectedText:     "summary\nline 1\nline 2",
+			expectedPerfdata: "a=1 b=2",
+		},
+		"fits": {
+			text:             "summary\nline 1",
+			perfdata:         "a=1",
+			maxLength:        18,
+			expectedText:     "summary\nline 1",
+			expectedPerfdata: "a=1",
+		},
+		"long-output": {
+			text:             "summary\nline 1\nline 2\nline 3",
+			perfdata:         "a=1 b=2",
+			maxLength:        34,
+			expectedText:     "summary\n(output truncated)",
+			expectedPerfdata: "a=1 b=2",
+		},
+		"long-summary": {
+			text:             "a very long summary\nline 1",
+			perfdata:         "a=1",
+			maxLength:        25,
+			expectedText:     "a very (output truncated)",
+			expectedPerfdata: "",
+		},
+		"long-perfdata": {
+			text:             "summary\nline 1\nline 2\nline 3",
+			perfdata:         "a=1 'label b'=2 c=3 d=4",
+			maxLength:        41,
+			expectedText:     "summary\n(output truncated)",
+			expectedPerfdata: "a=1 'label b'=2",
+		},
+		"multibyte": {
+			text:             "ääääääää\nline 1",
+			perfdata:         "",
+			maxLength:        22,
+			expectedText:     "ä (output truncated)",
+			expectedPerfdata: "",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			text, perfdata := TruncateOutput(tc.text, tc.perfdata, tc.maxLength)
+
+			if text != tc.expectedText {
+				t.Fatalf("expected %q, got %q", tc.expectedText, text)
+			}
+
+			if perfdata != tc.expectedPerfdata {
+				t.Fatalf("expected %q, got %q", tc.expectedPerfdata, perfdata)
+			}
+
+			if tc.maxLength > 0 && len(text)+len(perfdata) > tc.maxLength {
+				t.Fatalf("expected at most %d bytes, got %d", tc.maxLength, len(text)+len(perfdata))
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package check
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Units of measurement of the monitoring plugins guidelines
+//
+// See also: https://www.monitoring-plugins.org/doc/guidelines.html#AEN201
+const (
+	UomNone         = ""
+	UomSeconds      = "s"
+	UomMilliseconds = "ms"
+	UomMicroseconds = "us"
+	UomPercent      = "%"
+	UomBytes        = "B"
+	UomKilobytes    = "KB"
+	UomMegabytes    = "MB"
+	UomGigabytes    = "GB"
+	UomTerabytes    = "TB"
+	UomCounter      = "c"
+)
+
+// Additional units of measurement supported by Icinga 2
+//
+// See also: https://icinga.com/docs/icinga-2/latest/doc/05-service-monitoring/#unit-of-measurement-uom
+const (
+	UomNanoseconds = "ns"
+	UomMinutes     = "m"
+	UomHours       = "h"
+	UomDays        = "d"
+	UomPetabytes   = "PB"
+	UomExabytes    = "EB"
+	UomKibibytes   = "KiB"
+	UomMebibytes   = "MiB"
+	UomGibibytes   = "GiB"
+	UomTebibytes   = "TiB"
+	UomPebibytes   = "PiB"
+	UomExbibytes   = "EiB"
+	UomBits        = "b"
+	UomKilobits    = "kb"
+	UomMegabits    = "mb"
+	UomGigabits    = "gb"
+	UomTerabits    = "tb"
+	UomPetabits    = "pb"
+	UomExabits     = "eb"
+	UomKibibits    = "Kib"
+	UomMebibits    = "Mib"
+	UomGibibits    = "Gib"
+	UomTebibits    = "Tib"
+	UomPebibits    = "Pib"
+	UomExbibits    = "Eib"
+	UomPackets     = "packets"
+	UomAmperes     = "A"
+	UomVolts       = "V"
+	UomWatts       = "W"
+	UomAmpereHours = "Ah"
+	UomWattHours   = "Wh"
+	UomLumens      = "lm"
+	UomDecibelMW   = "dBm"
+	UomCelsius     = "C"
+	UomFahrenheit  = "F"
+	UomKelvin      = "K"
+	UomLiters      = "l"
+)
+
+// uomScale maps units to their base unit and the factor to convert a value to the base unit
+var uomScale = map[string]unitScale{
+	UomNanoseconds:  {UomSeconds, -1e9},
+	UomMicroseconds: {UomSeconds, -1e6},
+	UomMilliseconds: {UomSeconds, -1e3},
+	UomMinutes:      {UomSeconds, 60},
+	UomHours:        {UomSeconds, 3600},
+	UomDays:         {UomSeconds, 86400},
+	UomKilobytes:    {UomBytes, 1e3},
+	"kB":            {UomBytes, 1e3},
+	UomMegabytes:    {UomBytes, 1e6},
+	UomGigabytes:    {UomBytes, 1e9},
+	UomTerabytes:    {UomBytes, 1e12},
+	UomPetabytes:    {UomBytes, 1e15},
+	UomExabytes:     {UomBytes, 1e18},
+	UomKibibytes:    {UomBytes, 1 << 10},
+	UomMebibytes:    {UomBytes, 1 << 20},
+	UomGibibytes:    {UomBytes, 1 << 30},
+	UomTebibytes:    {UomBytes, 1 << 40},
+	UomPebibytes:    {UomBytes, 1 << 50},
+	UomExbibytes:    {UomBytes, 1 << 60},
+	UomKilobits:     {UomBits, 1e3},
+	UomMegabits:     {UomBits, 1e6},
+	UomGigabits:     {UomBits, 1e9},
+	UomTerabits:     {UomBits, 1e12},
+	UomPetabits:     {UomBits, 1e15},
+	UomExabits:      {UomBits, 1e18},
+	UomKibibits:     {UomBits, 1 << 10},
+	UomMebibits:     {UomBits, 1 << 20},
+	UomGibibits:     {UomBits, 1 << 30},
+	UomTebibits:     {UomBits, 1 << 40},
+	UomPebibits:     {UomBits, 1 << 50},
+	UomExbibits:     {UomBits, 1 << 60},
+}
+
+// unitScale converts a value to the base unit
+type unitScale struct {
+	base string
+	// factor to multiply the value with, a negative factor is a divisor to avoid rounding errors
+	factor float64
+}
+
+// apply converts the value to the base unit
+func (s unitScale) apply(value float64) float64 {
+	if s.factor < 0 {
+		return value / -s.factor
+	}
+
+	return value * s.factor
+}
+
+// validUoms contains all units without a scale, see ValidUom
+var validUoms = newValidUoms()
+
+// newValidUoms returns the units without a scale, the electrical units are also valid with a prefix
+func newValidUoms() map[string]bool {
+	valid := map[string]bool{}
+
+	for _, unit := range []string{UomNone, UomSeconds, UomPercent, UomBytes, UomBits, UomCounter, UomPackets,
+		UomLumens, UomDecibelMW, UomCelsius, UomFahrenheit, UomKelvin, "hl"} {
+		valid[unit] = true
+	}
+
+	for _, unit := range []string{UomAmperes, UomVolts, UomWatts, UomAmpereHours, UomWattHours, "As", "VA", UomLiters} {
+		for _, prefix := range []string{"", "n", "u", "m", "k"} {
+			valid[prefix+unit] = true
+		}
+	}
+
+	return valid
+}
+
+// NormalizeUnits enables the conversion of perfdata to the base unit of time, bytes and bits
+// (e.g. ms to s, KiB to B) in ValidatedString, see Perfdata.Normalize.
+var NormalizeUnits = false
+
+// ValidUom returns true if the unit of measurement is known by the monitoring plugins guidelines or Icinga 2.
+// Units are case-sensitive, e.g. "kb" are kilobits and "KB" kilobytes.
+func ValidUom(uom string) bool {
+	if validUoms[uom] {
+		return true
+	}
+
+	_, ok := uomScale[uom]
+
+	return ok
+}
+
+// Normalize returns a copy of the Perfdata with the value, thresholds and limits converted
+// to the base unit, e.g. 1500ms to 1.5s. Units without a base unit are not modified.
+//
+// The converted floats are formatted with up to 15 significant digits instead of three decimals,
+// e.g. 500us as 0.0005s.
+func (p Perfdata) Normalize() (Perfdata, error) {
+	scale, ok := uomScale[p.Uom]
+	if !ok {
+		return p, nil
+	}
+
+	return p.scale(scale)
+}
+
+// scaleThreshold returns a copy of the Threshold with both bounds converted to the base unit
+func scaleThreshold(t *Threshold, scale unitScale) *Threshold {
+	if t == nil {
+		return nil
+	}
+
+	// Infinite bounds stay infinite
+	return &Threshold{Inside: t.Inside, Lower: scale.apply(t.Lower), Upper: scale.apply(t.Upper)}
+}
+
+// scaleNumeric converts a numeric value to the base unit
+func scaleNumeric(value any, scale unitScale) (float64, error) {
+	// Durations are given in seconds, see formatNumeric
+	if d, ok := value.(time.Duration); ok {
+		return scale.apply(d.Seconds()), nil
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return scale.apply(float64(v.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return scale.apply(float64(v.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		if err := checkFloat(v.Float()); err != nil {
+			return 0, fmt.Errorf("can not scale perfdata value: %w", err)
+		}
+
+		return scale.apply(v.Float()), nil
+	}
+
+	// Other supported types, e.g. *big.Int or json.Number
+	formatted, err := formatNumeric(value)
+	if err != nil {
+		return 0, fmt.Errorf("can not scale perfdata value: %w", err)
+	}
+
+	f, err := strconv.ParseFloat(formatted, 64)
+	if err != nil {
+		return 0, fmt.Errorf("can not scale perfdata value: %w", err)
+	}
+
+	return scale.apply(f), nil
+}
+
+// ScaleTo returns a copy of the Perfdata with the value, thresholds and limits converted from the
+// base unit to uom, e.g. 1536B to 1.5KiB. This is the inverse of Normalize.
+func (p Perfdata) ScaleTo(uom string) (Perfdata, error) {
+	if p.Uom == uom {
+		return p, nil
+	}
+
+	scale, ok := uomScale[uom]
+	if !ok || scale.base != p.Uom {
+		return p, fmt.Errorf("can not convert perfdata from %q to %q", p.Uom, uom)
+	}
+
+	// The inverse of the conversion to the base unit
+	return p.scale(unitScale{base: uom, factor: -scale.factor})
+}
+
+// scale returns a copy of the Perfdata with the value, thresholds and limits converted with the unitScale
+func (p Perfdata) scale(scale unitScale) (Perfdata, error) {
+	switch value, err := scaleNumeric(p.Value, scale); {
+	case err == nil:
+		p.Value = value
+	case p.Value == UnknownValue{} || IsInvalidValue(err):
+		// Kept as it is, see InvalidValues
+	default:
+		return p, err
+	}
+
+	p.Uom = scale.base
+	p.exact = true
+	p.Warn = scaleThreshold(p.Warn, scale)
+	p.Crit = scaleThreshold(p.Crit, scale)
+
+	// Faulty limits are ignored in ValidatedString anyway
+	if p.Min != nil {
+		if limit, err := scaleNumeric(p.Min, scale); err == nil {
+			p.Min = limit
+		}
+	}
+
+	if p.Max != nil {
+		if limit, err := scaleNumeric(p.Max, scale); err == nil {
+			p.Max = limit
+		}
+	}
+
+	return p, nil
+}
+
+// BytesPerfdata returns Perfdata for a value in bytes converted to uom (UomBytes if empty)
+// and the value in the same unit for the output, e.g. "1.5GiB", so the output matches the graphs.
+//
+// The value, thresholds and limits of p are given in bytes, its Uom is ignored.
+//
+//	p, text, err := check.BytesPerfdata(check.Perfdata{Label: "used", Value: used, Min: 0, Max: total}, check.UomGibibytes)
+func BytesPerfdata(p Perfdata, uom string) (*Perfdata, string, error) {
+	bytes, err := scaleNumeric(p.Value, unitScale{factor: 1})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if bytes < 0 {
+		return nil, "", fmt.Errorf("byte value cannot be negative: %v", p.Value)
+	}
+
+	p.Uom = UomBytes
+
+	if uom == "" {
+		uom = UomBytes
+	}
+
+	scaled, err := p.ScaleTo(uom)
+	if err != nil {
+		return nil, "", err
+	}
+
+	value, err := scaled.formatNumeric(scaled.Value)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &scaled, value + scaled.Uom, nil
+}
+
+// DurationPerfdata returns Perfdata for a time.Duration converted to uom (UomSeconds if empty)
+// and the value in the same unit for the output, e.g. "1.5ms", so the output matches the graphs.
+//
+// Thresholds and limits of p are given in seconds, limits may also be a time.Duration. Its Uom is ignored.
+//
+//	p, text, err := check.DurationPerfdata(check.Perfdata{Label: "time", Value: elapsed, Min: 0}, check.UomMilliseconds)
+func DurationPerfdata(p Perfdata, uom string) (*Perfdata, string, error) {
+	d, ok := p.Value.(time.Duration)
+	if !ok {
+		return nil, "", fmt.Errorf("duration perfdata requires a time.Duration, got %T", p.Value)
+	}
+
+	p.Value = d.Seconds()
+	p.Uom = UomSeconds
+	p.exact = true
+
+	for _, limit := range []*any{&p.Min, &p.Max} {
+		if v, ok := (*limit).(time.Duration); ok {
+			*limit = v.Seconds()
+		}
+	}
+
+	if uom == "" {
+		uom = UomSeconds
+	}
+
+	scaled, err := p.ScaleTo(uom)
+	if err != nil {
+		return nil, "", err
+	}
+
+	value, err := scaled.formatNumeric(scaled.Value)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &scaled, value + scaled.Uom, nil
+}
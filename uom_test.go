@@ -0,0 +1,211 @@
+package check
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidUom(t *testing.T) {
+	for _, uom := range []string{"", "s", "ms", "%", "B", "KB", "kB", "KiB", "kb", "Mib", "c", "packets", "mA", "kWh", "dBm", "C"} {
+		if !ValidUom(uom) {
+			t.Fatalf("expected %q to be valid", uom)
+		}
+	}
+
+	for _, uom := range []string{"Ms", "sec", "Kb", "KIB", "bytes", " "} {
+		if ValidUom(uom) {
+			t.Fatalf("expected %q to be invalid", uom)
+		}
+	}
+
+	// Unknown units are still rendered, but reported by Validate
+	p := Perfdata{Label: "rate", Value: 5, Uom: "req/s"}
+
+	if p.String() != "rate=5req/s" {
+		t.Fatalf("expected %v, got %v", "rate=5req/s", p.String())
+	}
+
+	if err := p.Validate(); !errors.Is(err, ErrPerfdataUom) {
+		t.Fatalf("expected %v, got %v", ErrPerfdataUom, err)
+	}
+}
+
+func TestPerfdata_Normalize(t *testing.T) {
+	testcases := map[string]struct {
+		perfdata Perfdata
+		expected string
+	}{
+		"milliseconds": {
+			perfdata: Perfdata{
+				Label: "time",
+				Value: 1500,
+				Uom:   UomMilliseconds,
+				Warn:  &Threshold{Lower: 0, Upper: 1000},
+				Crit:  &Threshold{Lower: NegInf, Upper: 2000},
+				Min:   0,
+				Max:   uint(10000),
+			},
+			expected: "time=1.5s;1;~:2;0;10",
+		},
+		"microseconds": {
+			perfdata: Perfdata{Label: "t", Value: 500, Uom: UomMicroseconds, Warn: &Threshold{Upper: 250}},
+			expected: "t=0.0005s;0.00025",
+		},
+		"nanoseconds": {
+			perfdata: Perfdata{Label: "t", Value: 1200000, Uom: UomNanoseconds},
+			expected: "t=0.0012s",
+		},
+		"kilobytes": {
+			perfdata: Perfdata{Label: "memory", Value: 1.1, Uom: UomKilobytes},
+			expected: "memory=1100B",
+		},
+		"kibibytes": {
+			perfdata: Perfdata{Label: "memory", Value: 2.5, Uom: UomKibibytes},
+			expected: "memory=2560B",
+		},
+		"base-unit": {
+			perfdata: Perfdata{Label: "usage", Value: 42, Uom: UomPercent},
+			expected: "usage=42%",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			normalized, err := tc.perfdata.Normalize()
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if normalized.String() != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, normalized.String())
+			}
+		})
+	}
+
+	NormalizeUnits = true
+
+	defer func() {
+		NormalizeUnits = false
+	}()
+
+	p := Perfdata{Label: "time", Value: 500, Uom: UomMicroseconds}
+
+	if p.String() != "time=0.0005s" {
+		t.Fatalf("expected %v, got %v", "time=0.0005s", p.String())
+	}
+}
+
+func TestPerfdata_ScaleTo(t *testing.T) {
+	p := Perfdata{Label: "memory", Value: 1536, Uom: UomBytes, Warn: &Threshold{Upper: 2048}, Max: 4096}
+
+	scaled, err := p.ScaleTo(UomKibibytes)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if scaled.String() != "memory=1.5KiB;2;;;4" {
+		t.Fatalf("expected %v, got %v", "memory=1.5KiB;2;;;4", scaled.String())
+	}
+
+	if _, err := p.ScaleTo(UomMilliseconds); err == nil {
+		t.Fatalf("expected error, got none")
+	}
+}
+
+func TestBytesPerfdata(t *testing.T) {
+	testcases := map[string]struct {
+		uom      string
+		expected string
+		text     string
+	}{
+		"default": {
+			uom:      "",
+			expected: "used=1610612736B;;1073741824;0",
+			text:     "1610612736B",
+		},
+		"iec": {
+			uom:      UomGibibytes,
+			expected: "used=1.5GiB;;1;0",
+			text:     "1.5GiB",
+		},
+		"si": {
+			uom:      UomMegabytes,
+			expected: "used=1610.612736MB;;1073.741824;0",
+			text:     "1610.612736MB",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			p, text, err := BytesPerfdata(Perfdata{
+				Label: "used",
+				Value: uint64(1610612736),
+				Crit:  &Threshold{Upper: 1 << 30},
+				Min:   0,
+			}, tc.uom)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if p.String() != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, p.String())
+			}
+
+			if text != tc.text {
+				t.Fatalf("expected %v, got %v", tc.text, text)
+			}
+		})
+	}
+
+	if _, _, err := BytesPerfdata(Perfdata{Label: "used", Value: -1}, ""); err == nil {
+		t.Fatalf("expected error, got none")
+	}
+
+	if _, _, err := BytesPerfdata(Perfdata{Label: "used", Value: 1}, UomSeconds); err == nil {
+		t.Fatalf("expected error, got none")
+	}
+}
+
+func TestDurationPerfdata(t *testing.T) {
+	p, text, err := DurationPerfdata(Perfdata{
+		Label: "time",
+		Value: 1500 * time.Millisecond,
+		Warn:  &Threshold{Upper: 1},
+		Max:   10 * time.Second,
+	}, UomMilliseconds)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if p.String() != "time=1500ms;1000;;;10000" {
+		t.Fatalf("expected %v, got %v", "time=1500ms;1000;;;10000", p.String())
+	}
+
+	if text != "1500ms" {
+		t.Fatalf("expected %v, got %v", "1500ms", text)
+	}
+
+	for _, tc := range []struct {
+		duration time.Duration
+		uom      string
+		expected string
+	}{
+		{1500 * time.Microsecond, UomMilliseconds, "t=1.5ms"},
+		{500 * time.Microsecond, "", "t=0.0005s"},
+	} {
+		p, text, err := DurationPerfdata(Perfdata{Label: "t", Value: tc.duration}, tc.uom)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		// The text matches the perfdata
+		if p.String() != tc.expected || "t="+text != tc.expected {
+			t.Fatalf("expected %v, got %v and %v", tc.expected, p.String(), text)
+		}
+	}
+
+	if _, _, err := DurationPerfdata(Perfdata{Label: "time", Value: 1.5}, ""); err == nil {
+		t.Fatalf("expected error, got none")
+	}
+}
@@ -0,0 +1,120 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	// ErrPerfdataLabel is returned for empty labels and labels with characters that are replaced when rendered
+	ErrPerfdataLabel = errors.New("invalid perfdata label")
+	// ErrPerfdataUom is returned for an unknown unit of measurement, see ValidUom
+	ErrPerfdataUom = errors.New("invalid unit of measurement")
+	// ErrPerfdataLimit is returned for limits that are not a valid number and a min greater than max
+	ErrPerfdataLimit = errors.New("invalid perfdata limit")
+	// ErrPerfdataThreshold is returned for thresholds outside of the limits, usually caused by a unit mismatch
+	ErrPerfdataThreshold = errors.New("perfdata threshold outside of limits")
+	// ErrPerfdataDuplicate is returned for labels that occur more than once in a PerfdataList
+	ErrPerfdataDuplicate = errors.New("duplicate perfdata label")
+)
+
+// Validate returns all problems of the Perfdata joined with errors.Join, nil if there are none.
+//
+// In contrast to ValidatedString, faulty limits are reported as well as labels with characters
+// that are replaced and thresholds outside of the limits. A threshold is outside of the limits,
+// if a bound is greater than max or the upper bound is lower than min, e.g. thresholds in seconds
+// for a value in milliseconds. The errors can be checked with errors.Is, e.g. for ErrPerfdataLimit.
+func (p Perfdata) Validate() error {
+	var errs []error
+
+	label := p.fullLabel()
+
+	add := func(err error) {
+		errs = append(errs, fmt.Errorf("perfdata %s: %w", label, err))
+	}
+
+	if p.Label == "" {
+		add(fmt.Errorf("%w: empty label", ErrPerfdataLabel))
+	} else if replacer.Replace(label) != label {
+		add(fmt.Errorf("%w: %q contains one of = ` ' \"", ErrPerfdataLabel, label))
+	}
+
+	if _, err := formatNumeric(p.Value); err != nil {
+		add(err)
+	}
+
+	if !ValidUom(p.Uom) {
+		add(fmt.Errorf("%w: %q", ErrPerfdataUom, p.Uom))
+	}
+
+	minimum, maximum := math.Inf(-1), math.Inf(1)
+
+	for _, limit := range []struct {
+		name  string
+		value any
+		dest  *float64
+	}{{"min", p.Min, &minimum}, {"max", p.Max, &maximum}} {
+		if limit.value == nil {
+			continue
+		}
+
+		value, err := scaleNumeric(limit.value, unitScale{factor: 1})
+		if err != nil {
+			add(fmt.Errorf("%w: %s: %w", ErrPerfdataLimit, limit.name, err))
+			continue
+		}
+
+		*limit.dest = value
+	}
+
+	if minimum > maximum {
+		add(fmt.Errorf("%w: min %s greater than max %s", ErrPerfdataLimit, FormatFloat(minimum), FormatFloat(maximum)))
+	}
+
+	for _, threshold := range []struct {
+		name string
+		t    *Threshold
+	}{{"warning", p.Warn}, {"critical", p.Crit}} {
+		if threshold.t == nil {
+			continue
+		}
+
+		if threshold.t.Lower > maximum || (!math.IsInf(threshold.t.Upper, 1) && threshold.t.Upper > maximum) ||
+			threshold.t.Upper < minimum {
+			add(fmt.Errorf("%w: %s threshold %s, min %s, max %s", ErrPerfdataThreshold,
+				threshold.name, threshold.t, FormatFloat(minimum), FormatFloat(maximum)))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate returns all problems of the Perfdata in the list joined with errors.Join, nil if there are none.
+// Additionally to Perfdata.Validate, labels that occur more than once are reported with ErrPerfdataDuplicate.
+//
+// String omits faulty Perfdata silently, Validate can be used in tests, a strict mode or with --debug:
+//
+//	if err := list.Validate(); err != nil {
+//		logger.Debug("invalid perfdata", "error", err)
+//	}
+func (l *PerfdataList) Validate() error {
+	var errs []error
+
+	labels := map[string]int{}
+
+	for _, p := range *l {
+		if err := p.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+
+		label := replacer.Replace(p.fullLabel())
+
+		labels[label]++
+		if labels[label] == 2 {
+			errs = append(errs, fmt.Errorf("perfdata %s: %w", label, ErrPerfdataDuplicate))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,91 @@
+package check
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPerfdata_Validate(t *testing.T) {
+	testcases := map[string]struct {
+		perfdata Perfdata
+		expected []error
+	}{
+		"valid": {
+			perfdata: Perfdata{Label: "time", Value: 1, Uom: "s", Warn: &Threshold{Upper: 5}, Crit: &Threshold{Lower: 1, Upper: PosInf}, Min: 0, Max: 10},
+		},
+		"empty-label": {
+			perfdata: Perfdata{Value: 1},
+			expected: []error{ErrPerfdataLabel},
+		},
+		"label-chars": {
+			perfdata: Perfdata{Label: "a=b", Check: "it's", Value: 1},
+			expected: []error{ErrPerfdataLabel},
+		},
+		"value": {
+			perfdata: Perfdata{Label: "time", Value: math.NaN()},
+			expected: []error{ErrPerfdataNaN},
+		},
+		"uom": {
+			perfdata: Perfdata{Label: "time", Value: 1, Uom: "Ms"},
+			expected: []error{ErrPerfdataUom},
+		},
+		"limits": {
+			perfdata: Perfdata{Label: "time", Value: 1, Min: 10, Max: 5},
+			expected: []error{ErrPerfdataLimit},
+		},
+		"faulty-limit": {
+			perfdata: Perfdata{Label: "time", Value: 1, Max: math.Inf(1)},
+			expected: []error{ErrPerfdataLimit, ErrPerfdataInf},
+		},
+		"unit-mismatch": {
+			perfdata: Perfdata{Label: "time", Value: 1500, Uom: "ms", Warn: &Threshold{Upper: 1}, Crit: &Threshold{Upper: 20000}, Min: 0, Max: 10000},
+			expected: []error{ErrPerfdataThreshold},
+		},
+		"all": {
+			perfdata: Perfdata{Label: "'", Value: math.Inf(1), Uom: "x", Min: 1, Max: 0},
+			expected: []error{ErrPerfdataLabel, ErrPerfdataInf, ErrPerfdataUom, ErrPerfdataLimit},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.perfdata.Validate()
+
+			if len(tc.expected) == 0 && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			for _, expected := range tc.expected {
+				if !errors.Is(err, expected) {
+					t.Fatalf("expected %v, got %v", expected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestPerfdataList_Validate(t *testing.T) {
+	list := PerfdataList{
+		{Label: "load", Value: 1},
+		{Label: "usage", Check: "disk", Value: 42, Uom: "%"},
+		{Label: "usage", Value: 10, Uom: "%"},
+	}
+
+	if err := list.Validate(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	list.Add(&Perfdata{Label: "load", Value: 2})
+	list.Add(&Perfdata{Label: "load", Value: 3})
+	list.Add(&Perfdata{Label: "time", Value: 1, Min: 2, Max: 1})
+
+	err := list.Validate()
+
+	expected := "perfdata load: duplicate perfdata label\n" +
+		"perfdata time: invalid perfdata limit: min 2 greater than max 1"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err)
+	}
+}